@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
 	"crypto/sha256"
 	"crypto/tls"
 	"encoding/base64"
@@ -79,7 +80,7 @@ func proccessRequest[RetT any](req *http.Request) (result RetT, invInfo InvocInf
 
 		invInfo = respBody.InvocationInfo
 		result = respBody.Result
-	case http.StatusBadRequest:
+	case http.StatusBadRequest, http.StatusPreconditionFailed:
 		var respBody struct {
 			InvocationInfo InvocInfo       `json:"invocationInfo"`
 			Error          BadRequestError `json:"error"`
@@ -272,6 +273,18 @@ func TrackCoverLink(track *Track, size int) string {
 	return fmt.Sprintf("https://%s%dx%d", track.CoverUri[:len(track.CoverUri)-2], size, size)
 }
 
+// PlaylistCoverLink returns the playlist cover link or the first mosaic item link if the cover is not uploaded.
+func PlaylistCoverLink(playlist *Playlist, size int) string {
+	uri := playlist.Cover.Uri
+	if len(uri) == 0 && len(playlist.Cover.ItemsUri) > 0 {
+		uri = playlist.Cover.ItemsUri[0]
+	}
+	if len(uri) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("https://%s%dx%d", uri[:len(uri)-2], size, size)
+}
+
 func DownloadTrackCover(dst io.Writer, track *Track, size int) (string, error) {
 	url := TrackCoverLink(track, size)
 	if len(url) == 0 {
@@ -300,7 +313,6 @@ func NewClient(name, token string) (*YaMusicClient, error) {
 	}
 
 	client.userid = clientStatus.Account.Uid
-	client.sessionid = nowTimestamp()
 
 	return client, nil
 }
@@ -331,24 +343,58 @@ func (client *YaMusicClient) RenamePlaylist(kind uint64, newName string) (playli
 	return
 }
 
+func (client *YaMusicClient) SetPlaylistDescription(kind uint64, description string) (playlist Playlist, err error) {
+	playlist, _, err = postRequest[Playlist](client.token, fmt.Sprintf("/users/%d/playlists/%d/description", client.userid, kind), url.Values{
+		"value": {description},
+	})
+	return
+}
+
+func (client *YaMusicClient) SetPlaylistVisibility(kind uint64, public bool) (playlist Playlist, err error) {
+	var visibility string
+	if public {
+		visibility = "public"
+	} else {
+		visibility = "private"
+	}
+	playlist, _, err = postRequest[Playlist](client.token, fmt.Sprintf("/users/%d/playlists/%d/visibility", client.userid, kind), url.Values{
+		"value": {visibility},
+	})
+	return
+}
+
 func (client *YaMusicClient) RemovePlaylist(kind uint64) error {
 	_, _, err := postRequest[string](client.token, fmt.Sprintf("/users/%d/playlists/%d/delete", client.userid, kind), nil)
 	return err
 }
 
 func (client *YaMusicClient) AddToPlaylist(kind uint64, revision, pos int, trackId string) (playlist Playlist, err error) {
-	playlist, _, err = postRequest[Playlist](client.token, fmt.Sprintf("/users/%d/playlists/%d/change-relative", client.userid, kind), url.Values{
-		"diff":     {fmt.Sprintf(`{"diff":{"op":"insert","at":%d,"tracks":[{"id":"%s"}]}}`, pos, trackId)},
-		"revision": {fmt.Sprint(revision)},
-	})
-	return playlist, err
+	diff := PlaylistDiff{}.Insert(pos, PlaylistDiffTrack{Id: trackId})
+	return client.ChangePlaylist(kind, revision, diff)
 }
 
 func (client *YaMusicClient) RemoveFromPlaylist(kind uint64, revision, pos int) (playlist Playlist, err error) {
+	diff := PlaylistDiff{}.Delete(pos, pos+1)
+	return client.ChangePlaylist(kind, revision, diff)
+}
+
+// ChangePlaylist applies all diff operations to the playlist as a single change.
+// ErrWrongRevision is returned if the playlist was changed since the revision.
+func (client *YaMusicClient) ChangePlaylist(kind uint64, revision int, diff PlaylistDiff) (playlist Playlist, err error) {
+	diffData, err := json.Marshal(diff)
+	if err != nil {
+		return
+	}
+
 	playlist, _, err = postRequest[Playlist](client.token, fmt.Sprintf("/users/%d/playlists/%d/change-relative", client.userid, kind), url.Values{
-		"diff":     {fmt.Sprintf(`{"diff":{"op":"delete","from":%d,"to":%d}}`, pos, pos+1)},
+		"diff":     {string(diffData)},
 		"revision": {fmt.Sprint(revision)},
 	})
+
+	var reqErr BadRequestError
+	if errors.As(err, &reqErr) && reqErr.Name == "wrong-revision" {
+		err = ErrWrongRevision
+	}
 	return playlist, err
 }
 
@@ -357,6 +403,39 @@ func (client *YaMusicClient) ListPlaylists() (playlists []Playlist, err error) {
 	return
 }
 
+// UserPlaylists lists the public playlists of the user specified by the login or uid.
+func (client *YaMusicClient) UserPlaylists(user string) (playlists []Playlist, err error) {
+	playlists, _, err = getRequest[[]Playlist](client.token, fmt.Sprintf("/users/%s/playlists/list", url.PathEscape(user)), nil)
+	return
+}
+
+// LikedPlaylists lists the playlists of other users that we follow.
+func (client *YaMusicClient) LikedPlaylists() (playlists []Playlist, err error) {
+	likes, _, err := getRequest[[]LikePlaylistInfo](client.token, fmt.Sprintf("/users/%d/likes/playlists", client.userid), nil)
+	if err != nil {
+		return
+	}
+	playlists = make([]Playlist, len(likes))
+	for i := range likes {
+		playlists[i] = likes[i].Playlist
+	}
+	return
+}
+
+func (client *YaMusicClient) LikePlaylist(owner, kind uint64) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/playlists/add-multiple", client.userid), url.Values{"playlist-ids": {fmt.Sprintf("%d:%d", owner, kind)}})
+	return
+}
+
+func (client *YaMusicClient) UnlikePlaylist(owner, kind uint64) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/playlists/remove", client.userid), url.Values{"playlist-ids": {fmt.Sprintf("%d:%d", owner, kind)}})
+	return
+}
+
+func (client *YaMusicClient) UserId() uint64 {
+	return client.userid
+}
+
 func (client *YaMusicClient) Playlist(kind uint64) (playlist Playlist, err error) {
 	playlist, _, err = getRequest[Playlist](client.token, fmt.Sprintf("/users/%d/playlists/%d", client.userid, kind), nil)
 	return
@@ -469,21 +548,37 @@ func (client *YaMusicClient) RotorSessionTracks(sessionId string, feedbacks []*R
 	return
 }
 
-func (client *YaMusicClient) PlayTrack(track *Track, fromCache bool) (err error) {
+// PlayTrack reports the track playback to the user's listening history.
+// It should be sent once the playback is over with the actually played time and the position it was stopped at.
+func (client *YaMusicClient) PlayTrack(track *Track, playId string, playedSeconds, endPositionSeconds float64, fromCache bool) (err error) {
 	queryParams := url.Values{
 		"uid":                  {fmt.Sprint(client.userid)},
 		"from":                 {client.name},
-		"play-id":              {client.sessionid},
+		"play-id":              {playId},
 		"track-id":             {track.Id},
 		"from-cache":           {fmt.Sprint(fromCache)},
-		"track-length-seconds": {fmt.Sprint(track.DurationMs + 1000)},
-		"total-played-seconds": {fmt.Sprint(track.DurationMs + 1000)},
+		"track-length-seconds": {fmt.Sprintf("%.3f", float64(track.DurationMs)/1000.0)},
+		"total-played-seconds": {fmt.Sprintf("%.3f", playedSeconds)},
+		"end-position-seconds": {fmt.Sprintf("%.3f", endPositionSeconds)},
 		"timestamp":            {nowTimestamp()},
+		"client-now":           {nowTimestamp()},
+	}
+	if len(track.Albums) > 0 {
+		queryParams.Set("album-id", fmt.Sprint(track.Albums[0].Id))
 	}
 	_, _, err = postRequest[interface{}](client.token, "/play-audio", queryParams)
 	return
 }
 
+// NewPlayId returns a random id to distinguish one track playback from another.
+func NewPlayId() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
 func (client *YaMusicClient) LikedTracks() (tracks []LikeTrackInfo, err error) {
 	desc, _, err := getRequest[LikesDesc](client.token, fmt.Sprintf("/users/%d/likes/tracks", client.userid), nil)
 	if err != nil {
@@ -494,12 +589,54 @@ func (client *YaMusicClient) LikedTracks() (tracks []LikeTrackInfo, err error) {
 }
 
 func (client *YaMusicClient) LikeTrack(trackId string) (err error) {
-	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/tracks/add-multiple", client.userid), url.Values{"track-ids": {trackId}})
+	return client.LikeTracks([]string{trackId})
+}
+
+func (client *YaMusicClient) LikeTracks(trackIds []string) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/tracks/add-multiple", client.userid), url.Values{"track-ids": {strings.Join(trackIds, ",")}})
 	return
 }
 
 func (client *YaMusicClient) UnlikeTrack(trackId string) (err error) {
-	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/tracks/remove", client.userid), url.Values{"track-ids": {trackId}})
+	return client.UnlikeTracks([]string{trackId})
+}
+
+func (client *YaMusicClient) UnlikeTracks(trackIds []string) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/likes/tracks/remove", client.userid), url.Values{"track-ids": {strings.Join(trackIds, ",")}})
+	return
+}
+
+func (client *YaMusicClient) DislikedTracks() (tracks []LikeTrackInfo, err error) {
+	desc, _, err := getRequest[LikesDesc](client.token, fmt.Sprintf("/users/%d/dislikes/tracks", client.userid), nil)
+	if err != nil {
+		return
+	}
+	tracks = desc.Library.Tracks
+	return
+}
+
+func (client *YaMusicClient) DislikeTrack(trackId string) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/dislikes/tracks/add-multiple", client.userid), url.Values{"track-ids": {trackId}})
+	return
+}
+
+func (client *YaMusicClient) UndislikeTrack(trackId string) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/dislikes/tracks/remove", client.userid), url.Values{"track-ids": {trackId}})
+	return
+}
+
+func (client *YaMusicClient) DislikedArtists() (artists []Artist, err error) {
+	artists, _, err = getRequest[[]Artist](client.token, fmt.Sprintf("/users/%d/dislikes/artists", client.userid), url.Values{"with-timestamps": {"false"}})
+	return
+}
+
+func (client *YaMusicClient) DislikeArtist(artistId uint64) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/dislikes/artists/add-multiple", client.userid), url.Values{"artist-ids": {fmt.Sprint(artistId)}})
+	return
+}
+
+func (client *YaMusicClient) UndislikeArtist(artistId uint64) (err error) {
+	_, _, err = postRequest[interface{}](client.token, fmt.Sprintf("/users/%d/dislikes/artists/remove", client.userid), url.Values{"artist-ids": {fmt.Sprint(artistId)}})
 	return
 }
 
@@ -581,6 +718,16 @@ func (client *YaMusicClient) SearchSuggest(part string) (suggestions SearchSugge
 }
 
 func (client *YaMusicClient) TrackLyricsRequest(trackId string) (LRCLyrics []LyricPair, err error) {
+	lrc, err := client.TrackLyricsLRC(trackId)
+	if err != nil {
+		return []LyricPair{}, err
+	}
+	LRCLyrics = ParseLRCText(lrc)
+	return
+}
+
+// TrackLyricsLRC downloads the synced lyrics of the track in the LRC format.
+func (client *YaMusicClient) TrackLyricsLRC(trackId string) (string, error) {
 	timestamp := fmt.Sprintf("%d", time.Now().Unix())
 	// scary algorithm to sign the request (required for lyrics)
 	message := trackId + timestamp
@@ -590,21 +737,21 @@ func (client *YaMusicClient) TrackLyricsRequest(trackId string) (LRCLyrics []Lyr
 	sign := base64.StdEncoding.EncodeToString(hmacSign)
 	lyrics, _, err := getRequest[TrackLyrics](client.token, fmt.Sprintf("/tracks/%s/lyrics", trackId), url.Values{"sign": {sign}, "timeStamp": {timestamp}, "format": {"LRC"}})
 	if err != nil {
-		return []LyricPair{}, err
+		return "", err
 	}
 	LRCLyricsResponse, err := http.Get(lyrics.DownloadUrl)
 	if err != nil {
-		return []LyricPair{}, err
+		return "", err
 	}
+	defer LRCLyricsResponse.Body.Close()
 	data, err := io.ReadAll(LRCLyricsResponse.Body)
 	if err != nil {
-		return []LyricPair{}, err
+		return "", err
 	}
-	LRCLyrics = parseLRCText(string(data))
-	return
+	return string(data), nil
 }
 
-func parseLRCText(lrcContent string) []LyricPair {
+func ParseLRCText(lrcContent string) []LyricPair {
 	var lyrics []LyricPair
 	lines := strings.Split(lrcContent, "\n")
 
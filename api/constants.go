@@ -21,6 +21,8 @@ const (
 	ROTOR_SKIP           string = "skip"
 	ROTOR_LIKE           string = "like"
 	ROTOR_UNLIKE         string = "unlike"
+	ROTOR_DISLIKE        string = "dislike"
+	ROTOR_UNDISLIKE      string = "undislike"
 )
 
 var (
@@ -16,11 +16,13 @@ const (
 type TrackEventType string
 
 const (
-	EV_TRACK_STARTED  = TrackEventType(ROTOR_TRACK_STARTED)
-	EV_TRACK_FINISHED = TrackEventType(ROTOR_TRACK_FINISHED)
-	EV_TRACK_SKIPED   = TrackEventType(ROTOR_SKIP)
-	EV_TRACK_LIKED    = TrackEventType(ROTOR_LIKE)
-	EV_TRACK_UNLIKED  = TrackEventType(ROTOR_UNLIKE)
+	EV_TRACK_STARTED    = TrackEventType(ROTOR_TRACK_STARTED)
+	EV_TRACK_FINISHED   = TrackEventType(ROTOR_TRACK_FINISHED)
+	EV_TRACK_SKIPED     = TrackEventType(ROTOR_SKIP)
+	EV_TRACK_LIKED      = TrackEventType(ROTOR_LIKE)
+	EV_TRACK_UNLIKED    = TrackEventType(ROTOR_UNLIKE)
+	EV_TRACK_DISLIKED   = TrackEventType(ROTOR_DISLIKE)
+	EV_TRACK_UNDISLIKED = TrackEventType(ROTOR_UNDISLIKE)
 )
 
 type RotorFeedbackEvent struct {
@@ -47,11 +49,17 @@ func NewRadioFeedbackEvent(evType RadioEventType) *RotorFeedbackEvent {
 }
 
 func NewTrackFeedbackEvent(evType TrackEventType, track *Track, playedSeconds float64) *RotorFeedbackEvent {
+	// the track without the album is referred by its id only
+	trackId := track.Id
+	if len(track.Albums) > 0 {
+		trackId = fmt.Sprintf("%s:%d", track.Id, track.Albums[0].Id)
+	}
+
 	return &RotorFeedbackEvent{
 		Timestamp:          nowTimestamp(),
 		TotalPlayedSeconds: playedSeconds,
-		TrackLengthSeconds: float64(track.DurationMs) * 1000.0,
-		TrackId:            fmt.Sprintf("%s:%d", track.Id, track.Albums[0].Id),
+		TrackLengthSeconds: float64(track.DurationMs) / 1000.0,
+		TrackId:            trackId,
 		Type:               string(evType),
 	}
 }
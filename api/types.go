@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -12,10 +14,9 @@ type fullDownloadInfo struct {
 }
 
 type YaMusicClient struct {
-	name      string
-	token     string
-	userid    uint64
-	sessionid string
+	name   string
+	token  string
+	userid uint64
 }
 
 type BadRequestError struct {
@@ -27,6 +28,8 @@ func (e BadRequestError) Error() string {
 	return e.Name + ": " + e.Message
 }
 
+var ErrWrongRevision = errors.New("playlist revision is outdated")
+
 type UnauthorizedError struct {
 	Timestamp time.Time   `json:"timestamp"`
 	Path      string      `json:"path"`
@@ -123,6 +126,11 @@ type Album struct {
 	Volumes     [][]Track `json:"volumes"`
 	Artists     []Artist  `json:"artists"`
 	Labels      []Label   `json:"labels"`
+	// position of the track in the album, it's set in the track albums
+	TrackPosition struct {
+		Volume int `json:"volume"`
+		Index  int `json:"index"`
+	} `json:"trackPosition"`
 }
 
 type Track struct {
@@ -166,6 +174,43 @@ type Track struct {
 	RememberPosition bool   `json:"rememberPosition"`
 }
 
+type PlaylistDiffTrack struct {
+	Id      string `json:"id"`
+	AlbumId string `json:"albumId,omitempty"`
+}
+
+type playlistInsertOp struct {
+	Op     string              `json:"op"`
+	At     int                 `json:"at"`
+	Tracks []PlaylistDiffTrack `json:"tracks"`
+}
+
+type playlistDeleteOp struct {
+	Op   string `json:"op"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// PlaylistDiff is the list of the playlist operations applied one after another.
+type PlaylistDiff []any
+
+func (d PlaylistDiff) Insert(at int, tracks ...PlaylistDiffTrack) PlaylistDiff {
+	return append(d, playlistInsertOp{Op: "insert", At: at, Tracks: tracks})
+}
+
+// Delete removes the tracks in the range [from, to).
+func (d PlaylistDiff) Delete(from, to int) PlaylistDiff {
+	return append(d, playlistDeleteOp{Op: "delete", From: from, To: to})
+}
+
+func NewPlaylistDiffTrack(track *Track) PlaylistDiffTrack {
+	dt := PlaylistDiffTrack{Id: track.Id}
+	if len(track.Albums) > 0 {
+		dt.AlbumId = fmt.Sprint(track.Albums[0].Id)
+	}
+	return dt
+}
+
 type Playlist struct {
 	Uid  uint64 `json:"uid"`
 	Kind uint64 `json:"kind"`
@@ -294,6 +339,11 @@ type LikeTrackInfo struct {
 	Timestamp string `json:"timestamp"`
 }
 
+type LikePlaylistInfo struct {
+	Timestamp string   `json:"timestamp"`
+	Playlist  Playlist `json:"playlist"`
+}
+
 type LikesDesc struct {
 	Library struct {
 		Uid       uint64          `json:"uid"`
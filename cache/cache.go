@@ -1,9 +1,14 @@
 package cache
 
 import (
+	"crypto/sha256"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/config"
 )
 
@@ -34,7 +39,19 @@ func getCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
+// Read opens the cached track to be played and marks it as recently played.
 func Read(trackId string) (*os.File, int64, error) {
+	file, size, err := Open(trackId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index.played(trackId, time.Now())
+	return file, size, nil
+}
+
+// Open opens the cached track without marking it as played.
+func Open(trackId string) (*os.File, int64, error) {
 	dir, err := getCacheDir()
 	if err != nil {
 		return nil, 0, err
@@ -49,18 +66,26 @@ func Read(trackId string) (*os.File, int64, error) {
 	return file, stat.Size(), nil
 }
 
-func Write(trackId string) (*os.File, error) {
+// Write creates the temporary cache file of the track.
+// The track is added to the cache only when the file is committed.
+func Write(track *api.Track) (*File, error) {
 	dir, err := getCacheDir()
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := os.OpenFile(filepath.Join(dir, trackId+".mp3"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	path := filepath.Join(dir, track.Id+".mp3")
+	file, err := os.OpenFile(path+tempExt, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
 	if err != nil {
 		return nil, err
 	}
 
-	return file, nil
+	return &File{
+		file:  file,
+		path:  path,
+		track: *track,
+		hash:  sha256.New(),
+	}, nil
 }
 
 func Remove(trackId string) error {
@@ -69,5 +94,101 @@ func Remove(trackId string) error {
 		return err
 	}
 
-	return os.Remove(filepath.Join(dir, trackId+".mp3"))
+	err = os.Remove(filepath.Join(dir, trackId+".mp3"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	removeExtras(dir, trackId)
+
+	return index.remove(trackId)
+}
+
+// Evict removes the least recently played tracks until the cache size fits the limit.
+// The tracks that were never played since they were cached are ranked by their write time.
+// The tracks for which keep returns true are never removed.
+// It returns the ids of the removed tracks.
+func Evict(limit int64, keep func(trackId string) bool) ([]string, error) {
+	dir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	played := index.playTimes()
+
+	type cachedFile struct {
+		id     string
+		size   int64
+		played time.Time
+	}
+
+	var (
+		files []cachedFile
+		size  int64
+	)
+
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || ext != ".mp3" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		id := name[:len(name)-len(ext)]
+		playTime, ok := played[id]
+		if !ok {
+			playTime = info.ModTime()
+		}
+
+		files = append(files, cachedFile{
+			id:     id,
+			size:   info.Size(),
+			played: playTime,
+		})
+		size += info.Size()
+	}
+
+	if size <= limit {
+		return nil, nil
+	}
+
+	slices.SortFunc(files, func(a, b cachedFile) int {
+		return a.played.Compare(b.played)
+	})
+
+	var evicted []string
+	defer func() {
+		if len(evicted) > 0 {
+			index.remove(evicted...)
+		}
+	}()
+
+	for _, file := range files {
+		if size <= limit {
+			break
+		}
+		if keep(file.id) {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(dir, file.id+".mp3"))
+		if err != nil {
+			return evicted, err
+		}
+
+		removeExtras(dir, file.id)
+		size -= file.size
+		evicted = append(evicted, file.id)
+	}
+
+	return evicted, nil
 }
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	mpeg1Bitrates = [16]int{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320}
+	mpeg2Bitrates = [16]int{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160}
+	sampleRates   = [3]int{44100, 48000, 32000}
+)
+
+// EstimateDuration calculates the duration of the mp3 audio actually present in the file, ignoring the TLEN frame.
+// The frames count of the Xing header is used if it's present, reduced for the truncated file,
+// otherwise the file is considered to have a constant bitrate.
+func EstimateDuration(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 10)
+	_, err = io.ReadFull(file, header)
+	if err != nil {
+		return 0, err
+	}
+
+	var audioStart int64
+	if string(header[:3]) == "ID3" {
+		// the tag size is stored as a syncsafe integer
+		audioStart = 10 + (int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9]))
+		if header[5]&0x10 != 0 {
+			audioStart += 10
+		}
+	}
+
+	buf := make([]byte, 64*1024)
+	n, err := file.ReadAt(buf, audioStart)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	buf = buf[:n]
+
+	for i := 0; i+4 <= len(buf); i++ {
+		if buf[i] != 0xFF || buf[i+1]&0xE0 != 0xE0 {
+			continue
+		}
+
+		version := (buf[i+1] >> 3) & 0x03
+		layer := (buf[i+1] >> 1) & 0x03
+		bitrateIndex := buf[i+2] >> 4
+		rateIndex := (buf[i+2] >> 2) & 0x03
+		// only the valid layer III headers are accepted
+		if version == 1 || layer != 1 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+			continue
+		}
+
+		sampleRate := sampleRates[rateIndex]
+		bitrate := mpeg1Bitrates[bitrateIndex]
+		samplesPerFrame := 1152
+		sideInfo := 32
+		mono := buf[i+3]>>6 == 3
+		if mono {
+			sideInfo = 17
+		}
+		if version != 3 {
+			// MPEG 2 and 2.5
+			sampleRate /= 2
+			if version == 0 {
+				sampleRate /= 2
+			}
+			bitrate = mpeg2Bitrates[bitrateIndex]
+			samplesPerFrame = 576
+			sideInfo = 17
+			if mono {
+				sideInfo = 9
+			}
+		}
+
+		xing := i + 4 + sideInfo
+		if xing+12 <= len(buf) {
+			id := string(buf[xing : xing+4])
+			flags := binary.BigEndian.Uint32(buf[xing+4:])
+			if (id == "Xing" || id == "Info") && flags&0x01 != 0 {
+				frames := int64(binary.BigEndian.Uint32(buf[xing+8:]))
+				duration := frames * int64(samplesPerFrame) * 1000 / int64(sampleRate)
+				// the header keeps the full stream size, so the truncated file is shorter than it says
+				if flags&0x02 != 0 && xing+16 <= len(buf) {
+					streamSize := int64(binary.BigEndian.Uint32(buf[xing+12:]))
+					if audioSize := stat.Size() - audioStart - int64(i); streamSize > 0 && audioSize < streamSize {
+						duration = duration * audioSize / streamSize
+					}
+				}
+				return int(duration), nil
+			}
+		}
+
+		audioSize := stat.Size() - audioStart - int64(i)
+		return int(audioSize * 8 / int64(bitrate)), nil
+	}
+
+	return 0, errors.New("mp3 frame not found")
+}
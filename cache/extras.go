@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/bogem/id3v2/v2"
+)
+
+// The cover and the synced lyrics are kept next to the cached track,
+// so the track is displayed completely without the network.
+const (
+	coverExt  = ".jpg"
+	lyricsExt = ".lrc"
+)
+
+func extraPath(trackId, ext string) (string, error) {
+	dir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, trackId+ext), nil
+}
+
+func writeExtra(trackId, ext string, data []byte) error {
+	path, err := extraPath(trackId, ext)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path+tempExt, data, 0755)
+	if err == nil {
+		err = os.Rename(path+tempExt, path)
+	}
+	if err != nil {
+		os.Remove(path + tempExt)
+	}
+	return err
+}
+
+func readExtra(trackId, ext string) ([]byte, error) {
+	path, err := extraPath(trackId, ext)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// removeExtras removes the cover and the lyrics of the removed track.
+func removeExtras(dir, trackId string) {
+	os.Remove(filepath.Join(dir, trackId+coverExt))
+	os.Remove(filepath.Join(dir, trackId+lyricsExt))
+}
+
+func WriteCover(trackId string, cover []byte) error {
+	return writeExtra(trackId, coverExt, cover)
+}
+
+// ReadCover returns the cached cover of the track,
+// the cover is taken from the ID3 tag of the tracks cached without it.
+func ReadCover(trackId string) ([]byte, error) {
+	cover, err := readExtra(trackId, coverExt)
+	if err == nil {
+		return cover, nil
+	}
+
+	path, tagErr := extraPath(trackId, ".mp3")
+	if tagErr != nil {
+		return nil, err
+	}
+
+	tag, tagErr := id3v2.Open(path, id3v2.Options{Parse: true, ParseFrames: []string{"Attached picture"}})
+	if tagErr != nil {
+		return nil, err
+	}
+	defer tag.Close()
+
+	for _, frame := range tag.GetFrames(tag.CommonID("Attached picture")) {
+		if picture, ok := frame.(id3v2.PictureFrame); ok && len(picture.Picture) > 0 {
+			return picture.Picture, nil
+		}
+	}
+	return nil, err
+}
+
+// WriteLyrics stores the synced lyrics of the track in the LRC format.
+func WriteLyrics(trackId, lrc string) error {
+	return writeExtra(trackId, lyricsExt, []byte(lrc))
+}
+
+func ReadLyrics(trackId string) (string, error) {
+	data, err := readExtra(trackId, lyricsExt)
+	return string(data), err
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"encoding/hex"
+	"hash"
+	"os"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+// extension of the cache files that are not completely written yet
+const tempExt = ".part"
+
+// File is the cache file being written.
+// It's written to the temporary file that replaces the cached track only when committed,
+// so the interrupted writing never leaves the truncated track in the cache.
+type File struct {
+	file  *os.File
+	path  string
+	track api.Track
+	hash  hash.Hash
+	size  int64
+	done  bool
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	f.hash.Write(p[:n])
+	f.size += int64(n)
+	return n, err
+}
+
+// Commit moves the written file to the cache and stores the track with the file size and hash in the index.
+func (f *File) Commit() error {
+	if f.done {
+		return os.ErrClosed
+	}
+	f.done = true
+
+	err := f.file.Sync()
+	if err == nil {
+		err = f.file.Close()
+	}
+	if err == nil {
+		err = os.Rename(f.file.Name(), f.path)
+	}
+	if err != nil {
+		f.file.Close()
+		os.Remove(f.file.Name())
+		return err
+	}
+
+	return index.put(f.track.Id, indexEntry{
+		Track: f.track,
+		Size:  f.size,
+		Hash:  hex.EncodeToString(f.hash.Sum(nil)),
+	})
+}
+
+// Close discards the file if it wasn't committed.
+func (f *File) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	f.file.Close()
+	return os.Remove(f.file.Name())
+}
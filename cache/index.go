@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+const indexFileName = "index.json"
+
+// indexEntry is the cached track with the size and hash of its file written to the cache.
+// The hash is empty for the tracks restored from the ID3 tags.
+// The broken tracks are removed from the cache, but kept in the index to be downloaded again.
+type indexEntry struct {
+	Track  api.Track `json:"track"`
+	Size   int64     `json:"size"`
+	Hash   string    `json:"sha256,omitempty"`
+	Broken bool      `json:"broken,omitempty"`
+	// the last time the track was played from the cache, zero if it wasn't played yet
+	Played time.Time `json:"played,omitempty"`
+}
+
+// trackIndex keeps the full info of the cached tracks,
+// so the tracks don't need to be restored from the ID3 tags on every start.
+type trackIndex struct {
+	mu     sync.Mutex
+	tracks map[string]indexEntry
+}
+
+var index trackIndex
+
+func indexPath() (string, error) {
+	dir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, indexFileName), nil
+}
+
+// load reads the index file once.
+func (idx *trackIndex) load() error {
+	if idx.tracks != nil {
+		return nil
+	}
+
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	tracks := make(map[string]indexEntry)
+	err = json.Unmarshal(data, &tracks)
+	if err != nil {
+		return err
+	}
+
+	idx.tracks = tracks
+	return nil
+}
+
+func (idx *trackIndex) save() error {
+	path, err := indexPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(idx.tracks)
+	if err != nil {
+		return err
+	}
+
+	// the index is replaced only when it's completely written
+	err = os.WriteFile(path+tempExt, data, 0755)
+	if err == nil {
+		err = os.Rename(path+tempExt, path)
+	}
+	if err != nil {
+		os.Remove(path + tempExt)
+	}
+	return err
+}
+
+// loaded reads the index if it exists, the missing index is restored from the ID3 tags of the cached files.
+func (idx *trackIndex) loaded() error {
+	err := idx.load()
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	files, err := listFiles()
+	if err != nil {
+		return err
+	}
+
+	idx.tracks = make(map[string]indexEntry)
+	_, _, err = idx.rebuild(files)
+	return err
+}
+
+func (idx *trackIndex) put(trackId string, entry indexEntry) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	err := idx.loaded()
+	if err != nil {
+		return err
+	}
+
+	idx.tracks[trackId] = entry
+	return idx.save()
+}
+
+func (idx *trackIndex) remove(trackIds ...string) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	err := idx.loaded()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range trackIds {
+		delete(idx.tracks, id)
+	}
+	return idx.save()
+}
+
+// played records the last play time of the indexed track.
+func (idx *trackIndex) played(trackId string, t time.Time) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	err := idx.loaded()
+	if err != nil {
+		return err
+	}
+
+	entry, ok := idx.tracks[trackId]
+	if !ok {
+		return nil
+	}
+	entry.Played = t
+	idx.tracks[trackId] = entry
+	return idx.save()
+}
+
+// playTimes returns the last play times of the tracks that were played from the cache.
+func (idx *trackIndex) playTimes() map[string]time.Time {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	times := make(map[string]time.Time)
+	if idx.loaded() != nil {
+		return times
+	}
+
+	for id, entry := range idx.tracks {
+		if !entry.Played.IsZero() {
+			times[id] = entry.Played
+		}
+	}
+	return times
+}
+
+// rebuild drops the tracks that are missing in the files, except the broken ones,
+// and restores the tracks that are not indexed from their ID3 tags.
+func (idx *trackIndex) rebuild(files map[string]int64) (added, removed int, err error) {
+	err = idx.loaded()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for id, entry := range idx.tracks {
+		if _, ok := files[id]; !ok && !entry.Broken {
+			delete(idx.tracks, id)
+			removed++
+		}
+	}
+
+	for id, size := range files {
+		if entry, ok := idx.tracks[id]; ok && !entry.Broken {
+			continue
+		}
+		track, ok := readTrackTag(id)
+		if !ok {
+			continue
+		}
+		idx.tracks[id] = indexEntry{Track: track, Size: size}
+		added++
+	}
+
+	return added, removed, idx.save()
+}
+
+// RebuildIndex syncs the index with the tracks added to or deleted from the cache directory outside of the player.
+// It returns the number of the added and removed tracks.
+func RebuildIndex() (added, removed int, err error) {
+	files, err := listFiles()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	return index.rebuild(files)
+}
+
+func sortTracks(tracks []api.Track) {
+	slices.SortFunc(tracks, func(a, b api.Track) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bogem/id3v2/v2"
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+// MPEG 1 layer III frame header of 128 kbps and 44100 Hz, the frame is 417 bytes long
+var testFrameHeader = []byte{0xFF, 0xFB, 0x90, 0x00}
+
+const testFrameSize = 417
+
+// setupTestCache points the cache to the empty temporary directory and resets the index.
+func setupTestCache(t *testing.T) string {
+	dir := t.TempDir()
+	cacheDir := config.Current.CacheDir
+	config.Current.CacheDir = dir
+	index = trackIndex{}
+	t.Cleanup(func() {
+		config.Current.CacheDir = cacheDir
+		index = trackIndex{}
+	})
+	return dir
+}
+
+// writeTestTrack writes the tagged track of the frames count with the duration stored in TLEN.
+func writeTestTrack(t *testing.T, dir, trackId string, frames, durationMs int) {
+	file, err := os.Create(filepath.Join(dir, trackId+".mp3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	tag := id3v2.NewEmptyTag()
+	tag.SetTitle("Title " + trackId)
+	tag.SetArtist("Artist")
+	tag.SetAlbum("Album")
+	tag.AddTextFrame("TLEN", id3v2.EncodingUTF8, fmt.Sprint(durationMs))
+	_, err = tag.WriteTo(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frame := make([]byte, testFrameSize)
+	copy(frame, testFrameHeader)
+	for i := 0; i < frames; i++ {
+		_, err = file.Write(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVerifyRestoresMissingIndex(t *testing.T) {
+	dir := setupTestCache(t)
+	// 383 frames of 1152 samples are 10005 ms long
+	writeTestTrack(t, dir, "1", 383, 10005)
+
+	corrupt, err := Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 0 {
+		t.Fatalf("Verify() = %v, want no corrupt tracks", corrupt)
+	}
+
+	tracks, err := ListTracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 1 || tracks[0].Id != "1" {
+		t.Fatalf("ListTracks() = %v, want the track restored from the tag", tracks)
+	}
+
+	// the restored index is saved, so the track is kept after the restart
+	index = trackIndex{}
+	tracks, err = ListTracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("ListTracks() after reload = %v, want 1 track", tracks)
+	}
+}
+
+func TestVerifyDetectsTruncatedRestoredTrack(t *testing.T) {
+	dir := setupTestCache(t)
+	writeTestTrack(t, dir, "1", 383, 10005)
+	// the track is cut to 100 frames of 10005 ms
+	writeTestTrack(t, dir, "2", 100, 10005)
+
+	corrupt, err := Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 1 || corrupt[0].Track.Id != "2" {
+		t.Fatalf("Verify() = %v, want the truncated track 2", corrupt)
+	}
+}
+
+func TestEvictByPlayTime(t *testing.T) {
+	dir := setupTestCache(t)
+	writeTestTrack(t, dir, "1", 383, 10005)
+	writeTestTrack(t, dir, "2", 383, 10005)
+
+	for _, id := range []string{"1", "2"} {
+		file, _, err := Read(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+	}
+
+	// rewriting the file of the track 1 doesn't make it played
+	later := time.Now().Add(time.Hour)
+	err := os.Chtimes(filepath.Join(dir, "1.mp3"), later, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evicted, err := Evict(1, func(string) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted) == 0 || evicted[0] != "1" {
+		t.Fatalf("Evict() = %v, want the track 1 played first to be evicted first", evicted)
+	}
+}
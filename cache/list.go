@@ -10,7 +10,43 @@ import (
 	"github.com/dece2183/yamusic-tui/api"
 )
 
+// ListTracks returns the indexed tracks that are present in the cache directory.
+// The index is restored from the ID3 tags if it doesn't exist yet.
 func ListTracks() ([]api.Track, error) {
+	files, err := listFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	err = index.load()
+	if err != nil {
+		// the missing or broken index is restored from the tags
+		index.tracks = make(map[string]indexEntry)
+		_, _, err = index.rebuild(files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tracks := make([]api.Track, 0, len(files))
+	for id, size := range files {
+		entry, ok := index.tracks[id]
+		if !ok || entry.Broken {
+			continue
+		}
+		entry.Track.FileSize = int(size)
+		tracks = append(tracks, entry.Track)
+	}
+
+	sortTracks(tracks)
+	return tracks, nil
+}
+
+// listFiles returns the sizes of the cached tracks by their ids.
+func listFiles() (map[string]int64, error) {
 	dir, err := getCacheDir()
 	if err != nil {
 		return nil, err
@@ -21,7 +57,7 @@ func ListTracks() ([]api.Track, error) {
 		return nil, err
 	}
 
-	tracks := make([]api.Track, 0, len(entries))
+	files := make(map[string]int64, len(entries))
 	for _, entry := range entries {
 		name := entry.Name()
 		ext := strings.ToLower(filepath.Ext(name))
@@ -29,41 +65,62 @@ func ListTracks() ([]api.Track, error) {
 			continue
 		}
 
-		tag, err := id3v2.Open(filepath.Join(dir, name), id3v2.Options{Parse: true})
+		info, err := entry.Info()
 		if err != nil {
 			continue
 		}
+		files[name[:len(name)-len(ext)]] = info.Size()
+	}
 
-		artistNames := strings.Split(tag.Artist(), ",")
-		artists := make([]api.Artist, len(artistNames))
-		for i := range artistNames {
-			artists[i].Name = artistNames[i]
-		}
+	return files, nil
+}
 
-		stat, _ := entry.Info()
-		year, _ := strconv.Atoi(tag.Year())
-		durationMs, _ := strconv.Atoi(tag.GetTextFrame("TLEN").Text)
-
-		if durationMs > 0 {
-			tracks = append(tracks, api.Track{
-				Id:         name[:len(name)-len(ext)],
-				Title:      tag.Title(),
-				Available:  true,
-				FileSize:   int(stat.Size()),
-				DurationMs: int(durationMs),
-				Artists:    artists,
-				Albums: []api.Album{
-					{
-						Title: tag.Album(),
-						Genre: tag.Genre(),
-						Year:  year,
-					},
-				},
-			})
-		}
+// readTrackTag restores the track info from the ID3 tag of the cached file.
+// Only the info stored in the tag is restored.
+func readTrackTag(trackId string) (api.Track, bool) {
+	dir, err := getCacheDir()
+	if err != nil {
+		return api.Track{}, false
+	}
 
-		tag.Close()
+	track, err := ReadTag(filepath.Join(dir, trackId+".mp3"))
+	if err != nil || track.DurationMs <= 0 {
+		return api.Track{}, false
 	}
 
-	return tracks, nil
+	track.Id = trackId
+	return track, true
+}
+
+// ReadTag reads the track info from the ID3 tag of the mp3 file,
+// the duration is zero if the tag doesn't have it.
+func ReadTag(path string) (api.Track, error) {
+	tag, err := id3v2.Open(path, id3v2.Options{Parse: true})
+	if err != nil {
+		return api.Track{}, err
+	}
+	defer tag.Close()
+
+	artistNames := strings.Split(tag.Artist(), ",")
+	artists := make([]api.Artist, len(artistNames))
+	for i := range artistNames {
+		artists[i].Name = artistNames[i]
+	}
+
+	year, _ := strconv.Atoi(tag.Year())
+	durationMs, _ := strconv.Atoi(tag.GetTextFrame("TLEN").Text)
+
+	return api.Track{
+		Title:      tag.Title(),
+		Available:  true,
+		DurationMs: durationMs,
+		Artists:    artists,
+		Albums: []api.Album{
+			{
+				Title: tag.Album(),
+				Genre: tag.Genre(),
+				Year:  year,
+			},
+		},
+	}, nil
 }
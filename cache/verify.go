@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+// duration the audio may be shorter than the tagged track without being taken as truncated
+const truncationToleranceMs = 3000
+
+type Corruption struct {
+	Track  api.Track
+	Reason string
+}
+
+// Verify compares the cached files with the sizes stored in the index and with the hashes if checkHash is set.
+// The tracks restored from the ID3 tags have no hash, so their audio duration is compared with the track duration.
+// It returns the corrupt tracks.
+func Verify(checkHash bool) ([]Corruption, error) {
+	dir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	err = index.loaded()
+	if err != nil {
+		return nil, err
+	}
+
+	var corrupt []Corruption
+	for id, entry := range index.tracks {
+		if entry.Broken {
+			continue
+		}
+
+		size, ok := files[id]
+		if !ok {
+			continue
+		}
+		if size != entry.Size {
+			corrupt = append(corrupt, Corruption{entry.Track, fmt.Sprintf("size is %d instead of %d bytes", size, entry.Size)})
+			continue
+		}
+
+		path := filepath.Join(dir, id+".mp3")
+		if len(entry.Hash) == 0 {
+			duration, err := EstimateDuration(path)
+			if err != nil {
+				corrupt = append(corrupt, Corruption{entry.Track, err.Error()})
+			} else if duration < entry.Track.DurationMs-truncationToleranceMs {
+				corrupt = append(corrupt, Corruption{entry.Track, fmt.Sprintf("duration is %d instead of %d ms", duration, entry.Track.DurationMs)})
+			}
+			continue
+		}
+		if !checkHash {
+			continue
+		}
+
+		hash, err := fileHash(path)
+		if err != nil {
+			corrupt = append(corrupt, Corruption{entry.Track, err.Error()})
+		} else if hash != entry.Hash {
+			corrupt = append(corrupt, Corruption{entry.Track, "hash mismatch"})
+		}
+	}
+
+	return corrupt, nil
+}
+
+// ClearTemp removes the temporary files left by the interrupted writing.
+// It shouldn't be called while the tracks are written.
+func ClearTemp() error {
+	dir, err := getCacheDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), tempExt) {
+			err = os.Remove(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MarkBroken removes the corrupt tracks from the cache,
+// they are kept in the index to be downloaded again.
+func MarkBroken(trackIds ...string) error {
+	dir, err := getCacheDir()
+	if err != nil {
+		return err
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	err = index.loaded()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range trackIds {
+		err = os.Remove(filepath.Join(dir, id+".mp3"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		entry, ok := index.tracks[id]
+		if !ok {
+			continue
+		}
+		entry.Broken = true
+		index.tracks[id] = entry
+	}
+
+	return index.save()
+}
+
+// BrokenTracks returns the tracks removed from the cache as corrupt.
+func BrokenTracks() ([]api.Track, error) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	err := index.loaded()
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []api.Track
+	for _, entry := range index.tracks {
+		if entry.Broken {
+			tracks = append(tracks, entry.Track)
+		}
+	}
+
+	sortTracks(tracks)
+	return tracks, nil
+}
+
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/dece2183/yamusic-tui/cache"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+func cacheCommand(args []string) error {
+	flags := flag.NewFlagSet("cache", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yamusic-tui cache <rebuild|verify> [flags]")
+		fmt.Fprintln(flags.Output(), "\n  rebuild  sync the cache index with the tracks added or deleted outside of the player")
+		fmt.Fprintln(flags.Output(), "  verify   find the corrupt tracks")
+		fmt.Fprintln(flags.Output(), "\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	checkHash := flags.Bool("hash", false, "verify: compare the file hashes, it reads the whole cache")
+	repair := flags.Bool("repair", false, "verify: remove the corrupt tracks to download them again on the next player start")
+	remove := flags.Bool("remove", false, "verify: remove the corrupt tracks from the cache completely")
+
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	err := flags.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "rebuild":
+		return cacheRebuild()
+	case "verify":
+		return cacheVerify(*checkHash, *repair, *remove)
+	default:
+		flags.Usage()
+		return flag.ErrHelp
+	}
+}
+
+func cacheRebuild() error {
+	added, removed, err := cache.RebuildIndex()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Cache index is rebuilt: %d tracks added, %d removed\n", added, removed)
+	return nil
+}
+
+func cacheVerify(checkHash, repair, remove bool) error {
+	if repair && remove {
+		return errors.New("only one of -repair and -remove can be set")
+	}
+
+	err := cache.ClearTemp()
+	if err != nil {
+		return err
+	}
+
+	corrupt, err := cache.Verify(checkHash)
+	if err != nil {
+		return err
+	}
+
+	if len(corrupt) == 0 {
+		fmt.Println("No corrupt tracks found")
+		return nil
+	}
+
+	ids := make([]string, len(corrupt))
+	for i, c := range corrupt {
+		fmt.Printf("%s %s - %s: %s\n", c.Track.Id, helpers.ArtistList(c.Track.Artists), c.Track.Title, c.Reason)
+		ids[i] = c.Track.Id
+	}
+
+	switch {
+	case repair:
+		err = cache.MarkBroken(ids...)
+		if err == nil {
+			fmt.Printf("%d tracks will be downloaded again on the next start\n", len(ids))
+		}
+	case remove:
+		for _, id := range ids {
+			err = cache.Remove(id)
+			if err != nil {
+				break
+			}
+		}
+		if err == nil {
+			fmt.Printf("%d tracks are removed\n", len(ids))
+		}
+	default:
+		fmt.Printf("%d corrupt tracks found, run with -repair or -remove to fix them\n", len(ids))
+	}
+
+	return err
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"stats", "print the listening statistics", statsCommand},
+	{"scrobbler", "manage last.fm and listenbrainz scrobbling", scrobblerCommand},
+	{"cache", "manage the cached tracks", cacheCommand},
+	{"migrate", "import playlists and likes from Spotify or Apple Music", migrateCommand},
+}
+
+// Run executes the command line subcommand and returns the exit code.
+func Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage()
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		err := cmd.run(args[1:])
+		if err == flag.ErrHelp {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n", args[0])
+	usage()
+	return 2
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: yamusic-tui [command] [flags]")
+	fmt.Fprintln(os.Stderr, "\nRun without a command to start the player.")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(os.Stderr, "\nUse \"yamusic-tui [command] -h\" for the command flags.")
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/migrate"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+// number of the tracks added or liked with a single request
+const migrateBatchSize = 100
+
+func migrateCommand(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yamusic-tui migrate [flags] <export file>...")
+		fmt.Fprintln(flags.Output(), "\nCreate the playlists and likes from the Spotify or Apple Music CSV and JSON exports.")
+		fmt.Fprintln(flags.Output(), "\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	dryRun := flags.Bool("dry-run", false, "only match the tracks and print the report")
+	likes := flags.Bool("likes", false, "like the tracks of all playlists instead of creating the playlists")
+	public := flags.Bool("public", false, "make the created playlists public")
+	minScore := flags.Float64("min-score", migrate.DefaultMinScore, "minimal match score from 0 to 1")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	var playlists []migrate.Playlist
+	for _, path := range flags.Args() {
+		filePlaylists, err := migrate.Load(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		playlists = append(playlists, filePlaylists...)
+	}
+
+	if len(config.Current.Token) == 0 {
+		return errors.New("start the player to log in first")
+	}
+	api.SetupClient(config.Current.Proxy)
+	client, err := api.NewClient(config.DirName, config.Current.Token)
+	if err != nil {
+		return err
+	}
+
+	var (
+		likedIds  []string
+		liked     = make(map[string]bool)
+		matched   int
+		unmatched int
+		failed    int
+	)
+
+	for i := range playlists {
+		pl := &playlists[i]
+		tracks := matchPlaylist(client, pl, *minScore)
+		matched += len(tracks)
+		unmatched += len(pl.Entries) - len(tracks)
+
+		if *dryRun || len(tracks) == 0 {
+			continue
+		}
+
+		if pl.Likes || *likes {
+			for _, track := range tracks {
+				if !liked[track.Id] {
+					liked[track.Id] = true
+					likedIds = append(likedIds, track.Id)
+				}
+			}
+			continue
+		}
+
+		// the failed playlist doesn't stop the migration, the likes are still sent
+		err = createPlaylist(client, pl.Name, tracks, *public)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create playlist '%s': %s\n", pl.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Playlist '%s' is created\n", pl.Name)
+	}
+
+	var likeErr error
+	likedCount := 0
+	for start := 0; start < len(likedIds); start += migrateBatchSize {
+		batch := likedIds[start:min(start+migrateBatchSize, len(likedIds))]
+		likeErr = client.LikeTracks(batch)
+		if likeErr != nil {
+			break
+		}
+		likedCount += len(batch)
+	}
+	if likedCount > 0 {
+		fmt.Printf("%d tracks are liked\n", likedCount)
+	}
+
+	fmt.Printf("\n%d tracks matched, %d not found\n", matched, unmatched)
+	if likeErr != nil {
+		return fmt.Errorf("like tracks: %w", likeErr)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d playlists were not created", failed)
+	}
+	return nil
+}
+
+// matchPlaylist searches the playlist tracks and prints the entries that are not found.
+func matchPlaylist(client *api.YaMusicClient, pl *migrate.Playlist, minScore float64) []api.Track {
+	var (
+		tracks  []api.Track
+		reports []string
+	)
+
+	for i := range pl.Entries {
+		entry := &pl.Entries[i]
+		fmt.Fprintf(os.Stderr, "\rMatching '%s': %d/%d", pl.Name, i+1, len(pl.Entries))
+
+		result, err := client.Search(migrate.Query(entry), api.SEARCH_TRACK)
+		if err != nil {
+			reports = append(reports, fmt.Sprintf("  not found: %s: %s", entry.String(), err))
+			continue
+		}
+
+		track, score := migrate.BestMatch(entry, result.Tracks.Results)
+		if track == nil {
+			reports = append(reports, "  not found: "+entry.String())
+			continue
+		}
+		if score < minScore {
+			reports = append(reports, fmt.Sprintf("  not found: %s (closest: %s - %s, score %.2f)", entry.String(), helpers.ArtistList(track.Artists), track.Title, score))
+			continue
+		}
+		tracks = append(tracks, *track)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	kind := "playlist"
+	if pl.Likes {
+		kind = "likes"
+	}
+	fmt.Printf("%s '%s': %d of %d tracks matched\n", kind, pl.Name, len(tracks), len(pl.Entries))
+	for _, report := range reports {
+		fmt.Println(report)
+	}
+	return tracks
+}
+
+func createPlaylist(client *api.YaMusicClient, name string, tracks []api.Track, public bool) error {
+	pl, err := client.CreatePlaylist(name, public)
+	if err != nil {
+		return err
+	}
+
+	revision := pl.Revision
+	for start := 0; start < len(tracks); start += migrateBatchSize {
+		batch := tracks[start:min(start+migrateBatchSize, len(tracks))]
+		diffTracks := make([]api.PlaylistDiffTrack, len(batch))
+		for i := range batch {
+			diffTracks[i] = api.NewPlaylistDiffTrack(&batch[i])
+		}
+
+		pl, err = client.ChangePlaylist(pl.Kind, revision, api.PlaylistDiff{}.Insert(start, diffTracks...))
+		if err != nil {
+			return err
+		}
+		revision = pl.Revision
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/scrobbler"
+)
+
+func scrobblerCommand(args []string) error {
+	flags := flag.NewFlagSet("scrobbler", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: yamusic-tui scrobbler <lastfm-login|flush>")
+		fmt.Fprintln(flags.Output(), "\n  lastfm-login  obtain the last.fm session key and enable scrobbling")
+		fmt.Fprintln(flags.Output(), "  flush         submit the queued scrobbles")
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "lastfm-login":
+		return lastFmLogin()
+	case "flush":
+		return scrobblerFlush()
+	default:
+		flags.Usage()
+		return flag.ErrHelp
+	}
+}
+
+func lastFmLogin() error {
+	conf := config.Current.Scrobbling.LastFm
+	if len(conf.ApiKey) == 0 || len(conf.ApiSecret) == 0 {
+		return fmt.Errorf("set the last.fm api-key and api-secret in the config file at '%s' first", config.Path())
+	}
+
+	fmt.Print("Last.fm username: ")
+	username, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	username = strings.TrimSpace(username)
+
+	fmt.Print("Last.fm password: ")
+	password, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+
+	lastfm := scrobbler.NewLastFm(scrobbler.NewHttpClient(), conf)
+	sessionKey, err := lastfm.Login(username, string(password))
+	if err != nil {
+		return err
+	}
+
+	conf.SessionKey = sessionKey
+	conf.Enabled = true
+	err = config.Save()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Last.fm scrobbling is enabled")
+	return nil
+}
+
+func scrobblerFlush() error {
+	s := scrobbler.New()
+	if !s.Enabled() {
+		return errors.New("scrobbling is not configured")
+	}
+
+	s.FlushSync()
+	for name, count := range s.Pending() {
+		if count > 0 {
+			fmt.Printf("%s: %d scrobbles are left in the queue, see the log for details\n", name, count)
+		} else {
+			fmt.Printf("%s: all scrobbles are submitted\n", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/stats"
+)
+
+func statsCommand(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	period := flags.String("period", "year", "period to aggregate: "+strings.Join(stats.PeriodNames, ", ")+" or a year number")
+	format := flags.String("format", "text", "output format: text, json or csv")
+	sortBy := flags.String("sort", "plays", "sort items by: plays or time")
+	top := flags.Int("top", 10, "number of items in each category, 0 for all")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	p, err := stats.ParsePeriod(*period, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *sortBy != "plays" && *sortBy != "time" {
+		return fmt.Errorf("unknown sort '%s', expected plays or time", *sortBy)
+	}
+
+	report, err := stats.Compute(p)
+	if err != nil {
+		return err
+	}
+
+	report.Sort(*sortBy == "time")
+	if *top > 0 {
+		report = report.Top(*top)
+	}
+
+	switch *format {
+	case "text":
+		return writeStatsText(report)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format '%s', expected text, json or csv", *format)
+	}
+}
+
+func writeStatsText(r *stats.Report) error {
+	fmt.Printf("Listening statistics for %s: %d plays, %s\n", r.Period.Name, r.Plays, stats.FormatDuration(r.Time))
+
+	categories := []struct {
+		title string
+		items []stats.Item
+	}{
+		{"Top tracks", r.Tracks},
+		{"Top artists", r.Artists},
+		{"Top albums", r.Albums},
+		{"Top genres", r.Genres},
+	}
+
+	for _, c := range categories {
+		fmt.Printf("\n%s:\n", c.title)
+		if len(c.items) == 0 {
+			fmt.Println("  no data")
+			continue
+		}
+		for i, item := range c.items {
+			fmt.Printf("%3d. %s — %d plays, %s\n", i+1, item.Name, item.Plays, stats.FormatDuration(item.Time))
+		}
+	}
+
+	return nil
+}
@@ -66,11 +66,39 @@ func load() (Config, error) {
 		newConfig.VolumeStep = defaultConfig.VolumeStep
 	}
 
+	if newConfig.RotorLookahead <= 0 {
+		newConfig.RotorLookahead = defaultConfig.RotorLookahead
+	}
+
+	if newConfig.DownloadJobs <= 0 {
+		newConfig.DownloadJobs = defaultConfig.DownloadJobs
+	}
+
+	if len(newConfig.ExportTemplate) == 0 {
+		newConfig.ExportTemplate = defaultConfig.ExportTemplate
+	}
+
 	if newConfig.Search == nil {
 		search := *defaultConfig.Search
 		newConfig.Search = &search
 	}
 
+	if newConfig.Scrobbling == nil {
+		newConfig.Scrobbling = &Scrobbling{}
+	}
+	if newConfig.Scrobbling.LastFm == nil {
+		lastfm := *defaultConfig.Scrobbling.LastFm
+		newConfig.Scrobbling.LastFm = &lastfm
+	} else if newConfig.Scrobbling.LastFm.Endpoint == "" {
+		newConfig.Scrobbling.LastFm.Endpoint = defaultConfig.Scrobbling.LastFm.Endpoint
+	}
+	if newConfig.Scrobbling.ListenBrainz == nil {
+		listenbrainz := *defaultConfig.Scrobbling.ListenBrainz
+		newConfig.Scrobbling.ListenBrainz = &listenbrainz
+	} else if newConfig.Scrobbling.ListenBrainz.Endpoint == "" {
+		newConfig.Scrobbling.ListenBrainz.Endpoint = defaultConfig.Scrobbling.ListenBrainz.Endpoint
+	}
+
 	if newConfig.Controls == nil {
 		controls := *defaultConfig.Controls
 		newConfig.Controls = &controls
@@ -147,6 +175,16 @@ func Path() string {
 	return filepath.Join(configDir, "config.yaml")
 }
 
+// Dir returns the config directory, it is also used to store other app data.
+func Dir() string {
+	configDir, err := getDir()
+	if err != nil {
+		return ""
+	}
+
+	return configDir
+}
+
 func Save() error {
 	return save(Current)
 }
@@ -38,12 +38,53 @@ func (t CacheType) MarshalYAML() (interface{}, error) {
 	return cacheEnumToValue[t], nil
 }
 
+type RepeatMode uint
+
+const (
+	REPEAT_NONE RepeatMode = iota
+	REPEAT_ONE
+	REPEAT_ALL
+)
+
+var repeatValueToEnum = map[string]RepeatMode{
+	"none":     REPEAT_NONE,
+	"off":      REPEAT_NONE,
+	"false":    REPEAT_NONE,
+	"one":      REPEAT_ONE,
+	"track":    REPEAT_ONE,
+	"all":      REPEAT_ALL,
+	"playlist": REPEAT_ALL,
+}
+
+var repeatEnumToValue = map[RepeatMode]string{
+	REPEAT_NONE: "none",
+	REPEAT_ONE:  "one",
+	REPEAT_ALL:  "all",
+}
+
+func (t *RepeatMode) UnmarshalYAML(value *yaml.Node) error {
+	*t = repeatValueToEnum[value.Value]
+	return nil
+}
+
+func (t RepeatMode) MarshalYAML() (interface{}, error) {
+	if t > REPEAT_ALL {
+		t = REPEAT_NONE
+	}
+	return repeatEnumToValue[t], nil
+}
+
 type Icons struct {
 	Play       string `yaml:"play"`
 	Stop       string `yaml:"stop"`
 	Liked      string `yaml:"liked"`
 	NotLiked   string `yaml:"not-liked"`
+	Disliked   string `yaml:"disliked"`
+	Marked     string `yaml:"marked"`
 	Cached     string `yaml:"cached"`
+	RepeatOne  string `yaml:"repeat-one"`
+	RepeatAll  string `yaml:"repeat-all"`
+	Shuffle    string `yaml:"shuffle"`
 	LyricsDot  string `yaml:"lyrics-dot"`
 	VolumeOff  string `yaml:"volume-off"`
 	VolumeLow  string `yaml:"volume-low"`
@@ -83,24 +124,44 @@ type Controls struct {
 	Quit        *Key `yaml:"quit"`
 	Apply       *Key `yaml:"apply"`
 	Cancel      *Key `yaml:"cancel"`
+	Toggle      *Key `yaml:"toggle"`
 	CursorUp    *Key `yaml:"cursor-up"`
 	CursorDown  *Key `yaml:"cursor-down"`
 	Reload      *Key `yaml:"reload"`
 	ShowAllKeys *Key `yaml:"show-all-kyes"`
 	// Playlists control
-	PlaylistsUp     *Key `yaml:"playlists-up"`
-	PlaylistsDown   *Key `yaml:"playlists-down"`
-	PlaylistsRename *Key `yaml:"playlists-rename"`
-	PlaylistsHide   *Key `yaml:"playlists-hide"`
+	PlaylistsUp       *Key `yaml:"playlists-up"`
+	PlaylistsDown     *Key `yaml:"playlists-down"`
+	PlaylistsRename   *Key `yaml:"playlists-rename"`
+	PlaylistsEdit     *Key `yaml:"playlists-edit"`
+	PlaylistsOpenUser *Key `yaml:"playlists-open-user"`
+	PlaylistsPin      *Key `yaml:"playlists-pin"`
+	PlaylistsOffline  *Key `yaml:"playlists-offline"`
+	PlaylistsExport   *Key `yaml:"playlists-export"`
+	PlaylistsImport   *Key `yaml:"playlists-import"`
+	PlaylistsHide     *Key `yaml:"playlists-hide"`
 	// Track list control
 	TracksNextPage           *Key `yaml:"tracks-next-page"`
 	TracksPrevPage           *Key `yaml:"tracks-previous-page"`
 	TracksLike               *Key `yaml:"tracks-like"`
+	TracksDislike            *Key `yaml:"tracks-dislike"`
+	TracksDislikeArtist      *Key `yaml:"tracks-dislike-artist"`
+	TracksWave               *Key `yaml:"tracks-wave"`
+	TracksMark               *Key `yaml:"tracks-mark"`
+	TracksMarkRange          *Key `yaml:"tracks-mark-range"`
+	TracksMarkAll            *Key `yaml:"tracks-mark-all"`
+	TracksCache              *Key `yaml:"tracks-cache"`
+	TracksExport             *Key `yaml:"tracks-export"`
 	TracksAddToPlaylist      *Key `yaml:"tracks-add-to-playlist"`
 	TracksRemoveFromPlaylist *Key `yaml:"tracks-remove-from-playlist"`
 	TracksShare              *Key `yaml:"tracks-share"`
 	TracksShuffle            *Key `yaml:"tracks-shuffle"`
 	TracksSearch             *Key `yaml:"tracks-search"`
+	TracksAddToQueue         *Key `yaml:"tracks-add-to-queue"`
+	TracksPlayNext           *Key `yaml:"tracks-play-next"`
+	TracksQueueAll           *Key `yaml:"tracks-queue-all"`
+	TracksMoveUp             *Key `yaml:"tracks-move-up"`
+	TracksMoveDown           *Key `yaml:"tracks-move-down"`
 	TracksHide               *Key `yaml:"tracks-hide"`
 	// Player control
 	PlayerPause          *Key `yaml:"player-pause"`
@@ -109,11 +170,22 @@ type Controls struct {
 	PlayerRewindForward  *Key `yaml:"player-rewind-forward"`
 	PlayerRewindBackward *Key `yaml:"player-rewind-backward"`
 	PlayerLike           *Key `yaml:"player-like"`
+	PlayerDislike        *Key `yaml:"player-dislike"`
 	PlayerCache          *Key `yaml:"player-cache"`
 	PlayerVolUp          *Key `yaml:"player-vol-up"`
 	PlayerVolDown        *Key `yaml:"player-vol-down"`
 	PlayerToggleLyrics   *Key `yaml:"player-toggle-lyrics"`
+	PlayerRepeat         *Key `yaml:"player-repeat"`
 	PlayerHide           *Key `yaml:"player-hide"`
+	// Statistics control
+	StatsPeriod *Key `yaml:"stats-period"`
+	StatsSort   *Key `yaml:"stats-sort"`
+	// Downloads control
+	DownloadsPause    *Key `yaml:"downloads-pause"`
+	DownloadsPauseAll *Key `yaml:"downloads-pause-all"`
+	DownloadsCancel   *Key `yaml:"downloads-cancel"`
+	DownloadsRetry    *Key `yaml:"downloads-retry"`
+	DownloadsClear    *Key `yaml:"downloads-clear"`
 }
 
 type Search struct {
@@ -122,20 +194,48 @@ type Search struct {
 	Playlists bool `yaml:"playlists"`
 }
 
+type LastFm struct {
+	Enabled    bool   `yaml:"enabled"`
+	ApiKey     string `yaml:"api-key"`
+	ApiSecret  string `yaml:"api-secret"`
+	SessionKey string `yaml:"session-key"`
+	Endpoint   string `yaml:"endpoint"`
+}
+
+type ListenBrainz struct {
+	Enabled  bool   `yaml:"enabled"`
+	Token    string `yaml:"token"`
+	Endpoint string `yaml:"endpoint"`
+}
+
+type Scrobbling struct {
+	LastFm       *LastFm       `yaml:"lastfm"`
+	ListenBrainz *ListenBrainz `yaml:"listenbrainz"`
+}
+
 type Config struct {
-	Token          string    `yaml:"token"`
-	BufferSize     float64   `yaml:"buffer-size-ms"`
-	RewindDuration float64   `yaml:"rewind-duration-s"`
-	Volume         float64   `yaml:"volume"`
-	VolumeStep     float64   `yaml:"volume-step"`
-	SuppressErrors bool      `yaml:"suppress-errors"`
-	ShowLyrics     bool      `yaml:"show-lyrics"`
-	CacheTracks    CacheType `yaml:"cache-tracks"`
-	CacheDir       string    `yaml:"cache-dir"`
-	Proxy          string    `yaml:"proxy"`
-	Search         *Search   `yaml:"search"`
-	Controls       *Controls `yaml:"controls"`
-	Style          *Style    `yaml:"style"`
+	Token          string      `yaml:"token"`
+	BufferSize     float64     `yaml:"buffer-size-ms"`
+	RewindDuration float64     `yaml:"rewind-duration-s"`
+	Volume         float64     `yaml:"volume"`
+	VolumeStep     float64     `yaml:"volume-step"`
+	SuppressErrors bool        `yaml:"suppress-errors"`
+	ShowLyrics     bool        `yaml:"show-lyrics"`
+	Repeat         RepeatMode  `yaml:"repeat"`
+	SmartShuffle   bool        `yaml:"smart-shuffle"`
+	RotorLookahead int         `yaml:"rotor-lookahead"`
+	CacheTracks    CacheType   `yaml:"cache-tracks"`
+	CacheDir       string      `yaml:"cache-dir"`
+	CacheSizeLimit int         `yaml:"cache-size-limit-mb"`
+	DownloadJobs   int         `yaml:"download-jobs"`
+	ExportDir      string      `yaml:"export-dir"`
+	ExportTemplate string      `yaml:"export-template"`
+	MusicDirs      []string    `yaml:"music-dirs"`
+	Proxy          string      `yaml:"proxy"`
+	Search         *Search     `yaml:"search"`
+	Scrobbling     *Scrobbling `yaml:"scrobbling"`
+	Controls       *Controls   `yaml:"controls"`
+	Style          *Style      `yaml:"style"`
 }
 
 var defaultConfig = Config{
@@ -144,18 +244,36 @@ var defaultConfig = Config{
 	Volume:         0.5,
 	VolumeStep:     0.05,
 	ShowLyrics:     false,
+	Repeat:         REPEAT_NONE,
+	SmartShuffle:   true,
+	RotorLookahead: 5,
 	CacheTracks:    CACHE_LIKED_ONLY,
 	CacheDir:       "",
+	CacheSizeLimit: 0,
+	DownloadJobs:   3,
+	ExportDir:      "",
+	ExportTemplate: "{artist}/{album}/{track} - {title}",
 	SuppressErrors: false,
 	Search: &Search{
 		Artists:   true,
 		Albums:    false,
 		Playlists: false,
 	},
+	Scrobbling: &Scrobbling{
+		LastFm: &LastFm{
+			Enabled:  false,
+			Endpoint: "https://ws.audioscrobbler.com/2.0/",
+		},
+		ListenBrainz: &ListenBrainz{
+			Enabled:  false,
+			Endpoint: "https://api.listenbrainz.org",
+		},
+	},
 	Controls: &Controls{
 		Quit:                     NewKey("ctrl+q,ctrl+c"),
 		Apply:                    NewKey("enter"),
 		Cancel:                   NewKey("esc"),
+		Toggle:                   NewKey("tab"),
 		CursorUp:                 NewKey("up"),
 		CursorDown:               NewKey("down"),
 		Reload:                   NewKey("ctrl+\\"),
@@ -163,15 +281,34 @@ var defaultConfig = Config{
 		PlaylistsUp:              NewKey("ctrl+up"),
 		PlaylistsDown:            NewKey("ctrl+down"),
 		PlaylistsRename:          NewKey("ctrl+r"),
+		PlaylistsEdit:            NewKey("ctrl+e"),
+		PlaylistsOpenUser:        NewKey("ctrl+o"),
+		PlaylistsPin:             NewKey("ctrl+l"),
+		PlaylistsOffline:         NewKey("ctrl+k"),
+		PlaylistsExport:          NewKey("ctrl+w"),
+		PlaylistsImport:          NewKey("ctrl+g"),
 		PlaylistsHide:            NewKey("ctrl+b"),
 		TracksNextPage:           NewKey("pgup"),
 		TracksPrevPage:           NewKey("pgdown"),
 		TracksLike:               NewKey("l"),
+		TracksDislike:            NewKey("d"),
+		TracksDislikeArtist:      NewKey("ctrl+d"),
+		TracksWave:               NewKey("w"),
+		TracksMark:               NewKey("m"),
+		TracksMarkRange:          NewKey("v"),
+		TracksMarkAll:            NewKey("*"),
+		TracksCache:              NewKey("c"),
+		TracksExport:             NewKey("e"),
 		TracksAddToPlaylist:      NewKey("a"),
 		TracksRemoveFromPlaylist: NewKey("ctrl+a"),
 		TracksSearch:             NewKey("ctrl+f"),
 		TracksShuffle:            NewKey("ctrl+x"),
 		TracksShare:              NewKey("ctrl+s"),
+		TracksAddToQueue:         NewKey("q"),
+		TracksPlayNext:           NewKey("n"),
+		TracksQueueAll:           NewKey("Q"),
+		TracksMoveUp:             NewKey("shift+up"),
+		TracksMoveDown:           NewKey("shift+down"),
 		TracksHide:               NewKey("ctrl+t"),
 		PlayerPause:              NewKey("space"),
 		PlayerNext:               NewKey("right"),
@@ -179,11 +316,20 @@ var defaultConfig = Config{
 		PlayerRewindForward:      NewKey("ctrl+right"),
 		PlayerRewindBackward:     NewKey("ctrl+left"),
 		PlayerLike:               NewKey("L"),
+		PlayerDislike:            NewKey("D"),
 		PlayerToggleLyrics:       NewKey("t"),
+		PlayerRepeat:             NewKey("r"),
 		PlayerCache:              NewKey("S"),
 		PlayerVolUp:              NewKey("+,="),
 		PlayerVolDown:            NewKey("-"),
 		PlayerHide:               NewKey("ctrl+p"),
+		StatsPeriod:              NewKey("p"),
+		StatsSort:                NewKey("s"),
+		DownloadsPause:           NewKey("p"),
+		DownloadsPauseAll:        NewKey("P"),
+		DownloadsCancel:          NewKey("x"),
+		DownloadsRetry:           NewKey("R"),
+		DownloadsClear:           NewKey("c"),
 	},
 	Style: &Style{
 		VolumeIndicatorWidth:    16,
@@ -196,7 +342,12 @@ var defaultConfig = Config{
 			Stop:       "■",
 			Liked:      "💛",
 			NotLiked:   "🤍",
+			Disliked:   "👎",
+			Marked:     "•",
 			Cached:     "💿",
+			RepeatOne:  "🔂",
+			RepeatAll:  "🔁",
+			Shuffle:    "🔀",
 			LyricsDot:  "•",
 			VolumeOff:  "🔇",
 			VolumeLow:  "🔈",
@@ -0,0 +1,396 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+type State uint8
+
+const (
+	QUEUED State = iota
+	ACTIVE
+	PAUSED
+	DONE
+	FAILED
+	CANCELED
+)
+
+var stateNames = []string{"queued", "active", "paused", "done", "failed", "canceled"}
+
+func (s State) String() string {
+	if int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return "unknown"
+}
+
+// Finished reports whether the job won't be run anymore unless retried.
+func (s State) Finished() bool {
+	return s >= DONE
+}
+
+// minimal interval between the progress reports of the same job
+const reportInterval = 250 * time.Millisecond
+
+// FetchFunc downloads the track of the job.
+// It should stop as soon as the context is done and report the downloaded amount with the progress function.
+type FetchFunc func(ctx context.Context, track *api.Track, progress func(read, size int64)) error
+
+type Job struct {
+	Id    uint64
+	Task  string
+	Track api.Track
+	State State
+	Err   error
+	Tries int
+	Read  int64
+	Size  int64
+
+	fetch      FetchFunc
+	cancel     context.CancelFunc
+	stale      bool
+	running    bool
+	lastReport time.Time
+}
+
+// Result is reported after each job state change and periodically while the job is active.
+// Done, Failed and Total count the jobs added since all the previous ones were finished.
+type Result struct {
+	Job    Job
+	Done   int
+	Failed int
+	Total  int
+}
+
+// Manager runs the download jobs in the background
+// with a limited number of concurrent downloads.
+// The failed jobs are retried a limited number of times.
+type Manager struct {
+	report  func(Result)
+	workers int
+	retries int
+
+	mu     sync.Mutex
+	jobs   []*Job
+	nextId uint64
+	active int
+	paused bool
+}
+
+// New creates the download manager.
+// The report function is called from the worker goroutines.
+func New(workers, retries int, report func(Result)) *Manager {
+	return &Manager{
+		report:  report,
+		workers: max(workers, 1),
+		retries: max(retries, 0),
+	}
+}
+
+// Add queues the jobs of the task for the tracks that are not queued for the same task yet.
+// It returns the number of the added jobs.
+func (d *Manager) Add(task string, fetch FetchFunc, tracks ...api.Track) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.restartProgress()
+
+	var added int
+	for _, track := range tracks {
+		if d.isQueued(task, track.Id) {
+			continue
+		}
+		d.nextId++
+		d.jobs = append(d.jobs, &Job{
+			Id:    d.nextId,
+			Task:  task,
+			Track: track,
+			fetch: fetch,
+		})
+		added++
+	}
+
+	d.schedule()
+	return added
+}
+
+// Jobs returns the copies of all jobs in the queue order.
+func (d *Manager) Jobs() []Job {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	jobs := make([]Job, len(d.jobs))
+	for i, job := range d.jobs {
+		jobs[i] = *job
+	}
+	return jobs
+}
+
+// Progress returns the number of the finished, failed and all jobs
+// added since all the previous ones were finished.
+func (d *Manager) Progress() (done, failed, total int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.progress()
+}
+
+// Pause stops the job, the paused active job is started from the beginning when resumed.
+func (d *Manager) Pause(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.find(id)
+	if job == nil {
+		return
+	}
+
+	switch job.State {
+	case QUEUED:
+		job.State = PAUSED
+	case ACTIVE:
+		job.State = PAUSED
+		job.cancel()
+	}
+}
+
+func (d *Manager) Resume(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.find(id)
+	if job == nil || job.State != PAUSED {
+		return
+	}
+
+	job.State = QUEUED
+	d.schedule()
+}
+
+func (d *Manager) Cancel(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.find(id)
+	if job == nil || job.State.Finished() {
+		return
+	}
+
+	if job.State == ACTIVE {
+		job.cancel()
+	}
+	job.State = CANCELED
+}
+
+// Retry queues the failed or canceled job again.
+func (d *Manager) Retry(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	job := d.find(id)
+	if job == nil || (job.State != FAILED && job.State != CANCELED) {
+		return
+	}
+
+	d.restartProgress()
+
+	job.State = QUEUED
+	job.Err = nil
+	job.Tries = 0
+	job.stale = false
+	d.schedule()
+}
+
+// SetPaused stops starting the queued jobs, the active jobs are finished.
+func (d *Manager) SetPaused(paused bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paused = paused
+	d.schedule()
+}
+
+func (d *Manager) IsPaused() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.paused
+}
+
+// ClearFinished removes the finished jobs from the list.
+func (d *Manager) ClearFinished() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	jobs := d.jobs[:0]
+	for _, job := range d.jobs {
+		if !job.State.Finished() {
+			jobs = append(jobs, job)
+		}
+	}
+	clear(d.jobs[len(jobs):])
+	d.jobs = jobs
+}
+
+func (d *Manager) schedule() {
+	for _, job := range d.jobs {
+		if d.paused || d.active >= d.workers {
+			return
+		}
+		// the paused or canceled job may be still stopping
+		if job.State != QUEUED || job.running {
+			continue
+		}
+
+		var ctx context.Context
+		ctx, job.cancel = context.WithCancel(context.Background())
+		job.State = ACTIVE
+		job.Read, job.Size = 0, 0
+		job.running = true
+		d.active++
+		go d.run(ctx, job)
+	}
+}
+
+func (d *Manager) run(ctx context.Context, job *Job) {
+	track := job.Track
+	err := job.fetch(ctx, &track, func(read, size int64) {
+		d.mu.Lock()
+		job.Read, job.Size = read, size
+		if time.Since(job.lastReport) < reportInterval {
+			d.mu.Unlock()
+			return
+		}
+		job.lastReport = time.Now()
+		res := d.result(job)
+		d.mu.Unlock()
+
+		d.report(res)
+	})
+
+	d.mu.Lock()
+	job.cancel()
+	job.running = false
+	d.active--
+
+	if job.State == ACTIVE {
+		if err == nil {
+			job.State = DONE
+		} else {
+			job.Err = err
+			job.Tries++
+			if job.Tries > d.retries {
+				job.State = FAILED
+			} else {
+				// retry after the other queued jobs
+				job.State = QUEUED
+				d.moveToEnd(job)
+			}
+		}
+	}
+
+	res := d.result(job)
+	d.schedule()
+	d.mu.Unlock()
+
+	d.report(res)
+}
+
+func (d *Manager) result(job *Job) Result {
+	res := Result{Job: *job}
+	res.Done, res.Failed, res.Total = d.progress()
+	return res
+}
+
+func (d *Manager) progress() (done, failed, total int) {
+	for _, job := range d.jobs {
+		if job.stale {
+			continue
+		}
+		total++
+		if job.State.Finished() {
+			done++
+		}
+		if job.State == FAILED {
+			failed++
+		}
+	}
+	return
+}
+
+// restartProgress starts counting the progress again if all previous jobs are finished.
+func (d *Manager) restartProgress() {
+	if d.hasPending() {
+		return
+	}
+	for _, job := range d.jobs {
+		job.stale = true
+	}
+}
+
+func (d *Manager) hasPending() bool {
+	for _, job := range d.jobs {
+		if !job.State.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Manager) isQueued(task, trackId string) bool {
+	for _, job := range d.jobs {
+		if job.Task == task && job.Track.Id == trackId && !job.State.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Manager) find(id uint64) *Job {
+	for _, job := range d.jobs {
+		if job.Id == id {
+			return job
+		}
+	}
+	return nil
+}
+
+func (d *Manager) moveToEnd(job *Job) {
+	for i := range d.jobs {
+		if d.jobs[i] == job {
+			d.jobs = append(append(d.jobs[:i], d.jobs[i+1:]...), job)
+			return
+		}
+	}
+}
+
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	read     int64
+	size     int64
+	progress func(read, size int64)
+}
+
+// NewReader wraps the reader to report the read amount and stop reading when the context is done.
+func NewReader(ctx context.Context, reader io.Reader, size int64, progress func(read, size int64)) io.Reader {
+	return &progressReader{ctx: ctx, reader: reader, size: size, progress: progress}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.progress(r.read, r.size)
+	if errors.Is(err, io.EOF) {
+		return n, io.EOF
+	}
+	return n, err
+}
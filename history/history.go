@@ -0,0 +1,133 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+const (
+	fileName = "history.jsonl"
+	// number of the latest entries kept in memory
+	maxEntries = 1000
+)
+
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Played float64   `json:"played"`
+	Track  api.Track `json:"track"`
+}
+
+// History is the local playback history.
+// All entries are appended to the file in the config directory,
+// only the latest of them are loaded at the start.
+type History struct {
+	entries []Entry
+}
+
+func Path() string {
+	dir := config.Dir()
+	if len(dir) == 0 {
+		return ""
+	}
+	return filepath.Join(dir, fileName)
+}
+
+// Load reads the latest history entries from the file.
+// A missing file is not an error, the empty history is returned.
+func Load() (*History, error) {
+	h := &History{}
+
+	err := Read(func(e Entry) bool {
+		h.entries = append(h.entries, e)
+		if len(h.entries) > 2*maxEntries {
+			h.entries = append(h.entries[:0], h.entries[len(h.entries)-maxEntries:]...)
+		}
+		return true
+	})
+	if len(h.entries) > maxEntries {
+		h.entries = h.entries[len(h.entries)-maxEntries:]
+	}
+
+	return h, err
+}
+
+// Read calls the function for each entry in the history file from the oldest to the newest.
+// Reading stops when the function returns false. Malformed lines are skipped.
+func Read(fn func(Entry) bool) error {
+	path := Path()
+	if len(path) == 0 {
+		return errors.New("unable to locate the config directory")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var e Entry
+			if json.Unmarshal(line, &e) == nil && !fn(e) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Entries returns the loaded and added entries from the oldest to the newest.
+func (h *History) Entries() []Entry {
+	return h.entries
+}
+
+// Add appends the entry to the history and writes it to the file.
+// Only the latest entries are kept in memory, so the oldest one may be dropped.
+func (h *History) Add(e Entry) error {
+	h.entries = append(h.entries, e)
+	if len(h.entries) > maxEntries {
+		h.entries = h.entries[len(h.entries)-maxEntries:]
+	}
+
+	path := Path()
+	if len(path) == 0 {
+		return errors.New("unable to locate the config directory")
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
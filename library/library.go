@@ -0,0 +1,221 @@
+package library
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bogem/id3v2/v2"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+// extension of the exported files that are not completely written yet
+const tempExt = ".part"
+
+// Dir returns the library directory the tracks are exported to,
+// it's the Music directory in the user home by default.
+func Dir() (string, error) {
+	if len(config.Current.ExportDir) > 0 {
+		return filepath.Abs(config.Current.ExportDir)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Music"), nil
+}
+
+// TrackPath fills the path template with the track info.
+// The template may contain {artist}, {album_artist}, {album}, {year}, {disc}, {track}, {title} and {id},
+// the slashes separate the directories.
+func TrackPath(template string, track *api.Track) string {
+	var album api.Album
+	if len(track.Albums) > 0 {
+		album = track.Albums[0]
+	}
+
+	artist := "Unknown artist"
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0].Name
+	}
+	albumArtist := artist
+	if len(album.Artists) > 0 {
+		albumArtist = album.Artists[0].Name
+	}
+	albumTitle := album.Title
+	if len(albumTitle) == 0 {
+		albumTitle = "Unknown album"
+	}
+
+	replacer := strings.NewReplacer(
+		"{artist}", sanitize(artist),
+		"{album_artist}", sanitize(albumArtist),
+		"{album}", sanitize(albumTitle),
+		"{year}", fmt.Sprint(album.Year),
+		"{disc}", fmt.Sprint(max(album.TrackPosition.Volume, 1)),
+		"{track}", fmt.Sprintf("%02d", album.TrackPosition.Index),
+		"{title}", sanitize(trackTitle(track)),
+		"{id}", sanitize(track.Id),
+	)
+
+	parts := strings.Split(template, "/")
+	for i := range parts {
+		parts[i] = strings.Trim(replacer.Replace(parts[i]), " .")
+		if len(parts[i]) == 0 {
+			parts[i] = "_"
+		}
+	}
+	return filepath.Join(parts...) + ".mp3"
+}
+
+// Write exports the track audio with the complete tag to the library.
+// The audio may have its own tag, it's replaced.
+// The file is written only if the whole audio of the size is read, if the size is known.
+func Write(track *api.Track, audio io.Reader, size int64, cover []byte, lrc string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, TrackPath(config.Current.ExportTemplate, track))
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeAudio(path+tempExt, audio, size)
+	if err == nil {
+		err = writeTag(path+tempExt, track, cover, lrc)
+	}
+	if err == nil {
+		err = os.Rename(path+tempExt, path)
+	}
+	if err != nil {
+		os.Remove(path + tempExt)
+		return "", err
+	}
+
+	return path, nil
+}
+
+func writeAudio(path string, audio io.Reader, size int64) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(file, audio)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if size > 0 && written != size {
+		return fmt.Errorf("track is truncated to %d of %d bytes", written, size)
+	}
+	return closeErr
+}
+
+func writeTag(path string, track *api.Track, cover []byte, lrc string) error {
+	tag, err := id3v2.Open(path, id3v2.Options{Parse: true})
+	if err != nil {
+		return err
+	}
+	defer tag.Close()
+
+	tag.DeleteAllFrames()
+	SetTag(tag, track, cover, lrc)
+	return tag.Save()
+}
+
+// SetTag sets the complete tag of the track with the cover and the lyrics in the LRC format.
+func SetTag(tag *id3v2.Tag, track *api.Track, cover []byte, lrc string) {
+	tag.SetVersion(4)
+	tag.SetDefaultEncoding(id3v2.EncodingUTF8)
+	tag.SetTitle(trackTitle(track))
+	tag.SetArtist(helpers.ArtistList(track.Artists))
+	tag.AddTextFrame("TLEN", id3v2.EncodingUTF8, fmt.Sprint(track.DurationMs))
+
+	if len(track.Albums) > 0 {
+		album := &track.Albums[0]
+		tag.SetAlbum(album.Title)
+		if len(album.Genre) > 0 {
+			tag.SetGenre(album.Genre)
+		}
+		if album.Year > 0 {
+			tag.SetYear(fmt.Sprint(album.Year))
+		}
+
+		albumArtists := helpers.ArtistList(album.Artists)
+		if len(albumArtists) == 0 {
+			albumArtists = helpers.ArtistList(track.Artists)
+		}
+		tag.AddTextFrame(tag.CommonID("Band/Orchestra/Accompaniment"), id3v2.EncodingUTF8, albumArtists)
+
+		if album.TrackPosition.Index > 0 {
+			tag.AddTextFrame(tag.CommonID("Track number/Position in set"), id3v2.EncodingUTF8, fmt.Sprint(album.TrackPosition.Index))
+		}
+		if album.TrackPosition.Volume > 0 {
+			tag.AddTextFrame(tag.CommonID("Part of a set"), id3v2.EncodingUTF8, fmt.Sprint(album.TrackPosition.Volume))
+		}
+	}
+
+	if len(cover) > 0 {
+		tag.AddAttachedPicture(id3v2.PictureFrame{
+			Encoding:    id3v2.EncodingUTF8,
+			MimeType:    http.DetectContentType(cover),
+			PictureType: id3v2.PTFrontCover,
+			Picture:     cover,
+		})
+	}
+
+	lyrics := api.ParseLRCText(lrc)
+	if len(lyrics) > 0 {
+		lines := make([]string, len(lyrics))
+		for i, l := range lyrics {
+			lines[i] = l.Line
+		}
+		tag.AddUnsynchronisedLyricsFrame(id3v2.UnsynchronisedLyricsFrame{
+			Encoding:          id3v2.EncodingUTF8,
+			Language:          "und",
+			ContentDescriptor: "",
+			Lyrics:            strings.Join(lines, "\n"),
+		})
+		tag.AddFrame("SYLT", syncedLyricsFrame(lyrics))
+	}
+}
+
+// syncedLyricsFrame makes the SYLT frame which isn't supported by the id3v2 package.
+func syncedLyricsFrame(lyrics []api.LyricPair) id3v2.UnknownFrame {
+	// UTF-8 text, undefined language, timestamps in milliseconds, lyrics content, empty descriptor
+	body := []byte{id3v2.EncodingUTF8.Key, 'u', 'n', 'd', 2, 1, 0}
+	for _, l := range lyrics {
+		body = append(body, l.Line...)
+		body = append(body, 0)
+		body = binary.BigEndian.AppendUint32(body, uint32(l.Timestamp))
+	}
+	return id3v2.UnknownFrame{Body: body}
+}
+
+func trackTitle(track *api.Track) string {
+	if len(track.Version) > 0 {
+		return track.Title + " (" + track.Version + ")"
+	}
+	return track.Title
+}
+
+// sanitize removes the characters that are not allowed in the file names.
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,136 @@
+package library
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bogem/id3v2/v2"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/cache"
+)
+
+// prefix of the local music file track ids, the rest of the id is the file path
+const fileIdPrefix = "file:"
+
+// IsLocal reports whether the track is a local music file rather than the streamed one.
+func IsLocal(track *api.Track) bool {
+	return strings.HasPrefix(track.Id, fileIdPrefix)
+}
+
+// FilePath returns the path of the local music file.
+func FilePath(track *api.Track) string {
+	return strings.TrimPrefix(track.Id, fileIdPrefix)
+}
+
+// Scan lists the music files in the directories and their subdirectories.
+// Only mp3 files are listed as the player can't decode the other formats.
+// The track info is read from the ID3 tags, the file name is used if the title is missing.
+func Scan(dirs []string) ([]api.Track, error) {
+	var tracks []api.Track
+	for _, dir := range dirs {
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return tracks, err
+		}
+
+		err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// skip the unreadable directories
+				return nil
+			}
+			if entry.IsDir() || strings.ToLower(filepath.Ext(path)) != ".mp3" {
+				return nil
+			}
+
+			track, ok := readFileTrack(path)
+			if ok {
+				tracks = append(tracks, track)
+			}
+			return nil
+		})
+		if err != nil {
+			return tracks, err
+		}
+	}
+	return tracks, nil
+}
+
+// Open opens the local music file for playback.
+func Open(track *api.Track) (io.ReadCloser, int64, error) {
+	file, err := os.Open(FilePath(track))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+
+	return file, stat.Size(), nil
+}
+
+// ReadCover returns the front cover attached to the local music file.
+func ReadCover(track *api.Track) ([]byte, error) {
+	tag, err := id3v2.Open(FilePath(track), id3v2.Options{Parse: true, ParseFrames: []string{"Attached picture"}})
+	if err != nil {
+		return nil, err
+	}
+	defer tag.Close()
+
+	var cover []byte
+	for _, frame := range tag.GetFrames(tag.CommonID("Attached picture")) {
+		picture, ok := frame.(id3v2.PictureFrame)
+		if !ok {
+			continue
+		}
+		if picture.PictureType == id3v2.PTFrontCover {
+			return picture.Picture, nil
+		}
+		if cover == nil {
+			cover = picture.Picture
+		}
+	}
+	if cover == nil {
+		return nil, os.ErrNotExist
+	}
+	return cover, nil
+}
+
+// ReadLyrics returns the synced lyrics from the LRC file placed next to the local music file.
+func ReadLyrics(track *api.Track) (string, error) {
+	path := FilePath(track)
+	lrc, err := os.ReadFile(strings.TrimSuffix(path, filepath.Ext(path)) + ".lrc")
+	if err != nil {
+		return "", err
+	}
+	return string(lrc), nil
+}
+
+func readFileTrack(path string) (api.Track, bool) {
+	track, err := cache.ReadTag(path)
+	if err != nil {
+		return api.Track{}, false
+	}
+
+	if track.DurationMs <= 0 {
+		track.DurationMs, err = cache.EstimateDuration(path)
+		if err != nil || track.DurationMs <= 0 {
+			return api.Track{}, false
+		}
+	}
+
+	track.Id = fileIdPrefix + path
+	if len(track.Title) == 0 {
+		name := filepath.Base(path)
+		track.Title = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	if len(track.Artists) == 1 && len(track.Artists[0].Name) == 0 {
+		track.Artists = nil
+	}
+	return track, true
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+
 	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/cli"
 	"github.com/dece2183/yamusic-tui/config"
 	"github.com/dece2183/yamusic-tui/log"
 	"github.com/dece2183/yamusic-tui/media"
@@ -20,6 +23,12 @@ func main() {
 		log.Print(log.LVL_WARNIGN, "config load error: %s", err.Error())
 	}
 
+	if len(os.Args) > 1 {
+		code := cli.Run(os.Args[1:])
+		log.Stop()
+		os.Exit(code)
+	}
+
 	style.Apply(config.Current.Style)
 	api.SetupClient(config.Current.Proxy)
 
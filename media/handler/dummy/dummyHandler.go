@@ -42,5 +42,8 @@ func (*DummyHandler) OnPlayback() {
 func (*DummyHandler) OnPlayPause() {
 }
 
+func (*DummyHandler) OnOptions() {
+}
+
 func (*DummyHandler) OnSeek(position time.Duration) {
 }
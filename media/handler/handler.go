@@ -20,11 +20,13 @@ const (
 
 	MSG_GET_PLAYBACKSTATUS
 	MSG_GET_SHUFFLE
+	MSG_GET_LOOPSTATUS
 	MSG_GET_METADATA
 	MSG_GET_VOLUME
 	MSG_GET_POSITION
 
 	MSG_SET_SHUFFLE
+	MSG_SET_LOOPSTATUS
 	MSG_SET_VOLUME
 )
 
@@ -53,6 +55,14 @@ const (
 	STATE_PLAYING
 )
 
+type LoopStatus int
+
+const (
+	LOOP_NONE LoopStatus = iota
+	LOOP_TRACK
+	LOOP_PLAYLIST
+)
+
 type MediaHandler interface {
 	Start(handler func() error) error
 
@@ -63,5 +73,6 @@ type MediaHandler interface {
 	OnVolume()
 	OnPlayback()
 	OnPlayPause()
+	OnOptions()
 	OnSeek(position time.Duration)
 }
@@ -280,6 +280,9 @@ func (mh *MacosHandler) OnPlayback() {
 func (mh *MacosHandler) OnPlayPause() {
 }
 
+func (mh *MacosHandler) OnOptions() {
+}
+
 func (mh *MacosHandler) OnSeek(position time.Duration) {
 }
 
@@ -115,6 +115,70 @@ func (mh *MprisHandler) SetRate(float64) error {
 	return nil
 }
 
+func (mh *MprisHandler) LoopStatus() (types.LoopStatus, error) {
+	mh.msgChan <- handler.Message{
+		Type: handler.MSG_GET_LOOPSTATUS,
+	}
+
+	resp, ok := (<-mh.ansChan).(handler.LoopStatus)
+	if !ok {
+		return types.LoopStatusNone, fmt.Errorf("wrong loop status type")
+	}
+
+	switch resp {
+	case handler.LOOP_NONE:
+		return types.LoopStatusNone, nil
+	case handler.LOOP_TRACK:
+		return types.LoopStatusTrack, nil
+	case handler.LOOP_PLAYLIST:
+		return types.LoopStatusPlaylist, nil
+	}
+
+	return types.LoopStatusNone, fmt.Errorf("unknown loop status")
+}
+
+func (mh *MprisHandler) SetLoopStatus(status types.LoopStatus) error {
+	var loop handler.LoopStatus
+
+	switch status {
+	case types.LoopStatusNone:
+		loop = handler.LOOP_NONE
+	case types.LoopStatusTrack:
+		loop = handler.LOOP_TRACK
+	case types.LoopStatusPlaylist:
+		loop = handler.LOOP_PLAYLIST
+	default:
+		return fmt.Errorf("unknown loop status")
+	}
+
+	mh.msgChan <- handler.Message{
+		Type: handler.MSG_SET_LOOPSTATUS,
+		Arg:  loop,
+	}
+	return nil
+}
+
+func (mh *MprisHandler) Shuffle() (bool, error) {
+	mh.msgChan <- handler.Message{
+		Type: handler.MSG_GET_SHUFFLE,
+	}
+
+	resp, ok := (<-mh.ansChan).(bool)
+	if !ok {
+		return false, fmt.Errorf("wrong shuffle type")
+	}
+
+	return resp, nil
+}
+
+func (mh *MprisHandler) SetShuffle(shuffle bool) error {
+	mh.msgChan <- handler.Message{
+		Type: handler.MSG_SET_SHUFFLE,
+		Arg:  shuffle,
+	}
+	return nil
+}
+
 func (mh *MprisHandler) Metadata() (md types.Metadata, err error) {
 	mh.msgChan <- handler.Message{
 		Type: handler.MSG_GET_METADATA,
@@ -71,6 +71,10 @@ func (mh *MprisHandler) OnPlayPause() {
 	mh.evHandler.Player.OnPlayPause()
 }
 
+func (mh *MprisHandler) OnOptions() {
+	mh.evHandler.Player.OnOptions()
+}
+
 func (mh *MprisHandler) OnSeek(position time.Duration) {
 	mh.evHandler.Player.OnSeek(types.Microseconds(position.Microseconds()))
 }
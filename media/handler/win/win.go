@@ -109,6 +109,9 @@ func (wh *WinHandler) OnPlayPause() {
 	wh.setState(wh.playState)
 }
 
+func (wh *WinHandler) OnOptions() {
+}
+
 func (wh *WinHandler) OnSeek(position time.Duration) {
 	wh.updateTimeLineProperties(wh.trackDuration, position)
 
@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/playlistfile"
+)
+
+// DefaultMinScore is the minimal score of the track to be taken as the match.
+const DefaultMinScore = 0.8
+
+// duration difference that is not penalized and the difference that makes the duration score zero
+const (
+	durationSlackMs = 2000
+	durationLimitMs = 20000
+)
+
+// decorationRegexp matches the title decorations that differ between the services,
+// like "(feat. Artist)" or "- Remastered 2011".
+var decorationRegexp = regexp.MustCompile(`(?i)\s*[(\[](feat|ft|with)\.?\s[^)\]]*[)\]]|\s+-\s+[^-]*(remaster|version|edit|mono|stereo|live)[^-]*$`)
+
+func cleanTitle(title string) string {
+	return playlistfile.Normalize(decorationRegexp.ReplaceAllString(title, ""))
+}
+
+// Query returns the search request for the entry without the title decorations.
+func Query(entry *playlistfile.Entry) string {
+	title := decorationRegexp.ReplaceAllString(entry.Title, "")
+	if len(entry.Artists) == 0 {
+		return title
+	}
+	return entry.Artists[0] + " " + title
+}
+
+// BestMatch returns the available search result with the highest score and the score.
+func BestMatch(entry *playlistfile.Entry, candidates []api.Track) (*api.Track, float64) {
+	var (
+		best      *api.Track
+		bestScore float64
+	)
+	for i := range candidates {
+		if !candidates[i].Available {
+			continue
+		}
+		if score := Score(entry, &candidates[i]); score > bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	return best, bestScore
+}
+
+// Score rates the similarity of the track to the entry from 0 to 1.
+// The title is weighted the most, then the artists and the duration if it's known.
+func Score(entry *playlistfile.Entry, track *api.Track) float64 {
+	title := cleanTitle(entry.Title)
+	titleScore := max(
+		similarity(title, cleanTitle(track.Title)),
+		similarity(title, cleanTitle(track.Title+" "+track.Version)),
+	)
+
+	artistScore := 1.0
+	if len(entry.Artists) > 0 {
+		names := make([]string, len(track.Artists))
+		for i := range track.Artists {
+			names[i] = track.Artists[i].Name
+		}
+
+		// the artists may be listed separately or joined like "A & B"
+		artistScore = similarity(playlistfile.Normalize(strings.Join(entry.Artists, "")), playlistfile.Normalize(strings.Join(names, "")))
+		for _, a := range entry.Artists {
+			for _, b := range names {
+				artistScore = max(artistScore, similarity(playlistfile.Normalize(a), playlistfile.Normalize(b)))
+			}
+		}
+	}
+
+	durationScore := 1.0
+	if entry.DurationMs > 0 && track.DurationMs > 0 {
+		diff := entry.DurationMs - track.DurationMs
+		if diff < 0 {
+			diff = -diff
+		}
+		durationScore = 1 - float64(min(max(diff-durationSlackMs, 0), durationLimitMs))/durationLimitMs
+	}
+
+	return 0.55*titleScore + 0.3*artistScore + 0.15*durationScore
+}
+
+// similarity returns 1 for the equal strings and down to 0 for the completely different ones,
+// it's based on the edit distance.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	return 1 - float64(distance(ra, rb))/float64(max(len(ra), len(rb)))
+}
+
+// distance returns the Levenshtein distance of the strings.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
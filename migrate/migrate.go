@@ -0,0 +1,283 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/dece2183/yamusic-tui/playlistfile"
+)
+
+// Playlist is the playlist of the other service export.
+// The liked tracks or the library tracks are exported as the playlist with Likes set.
+type Playlist struct {
+	Name    string
+	Likes   bool
+	Entries []playlistfile.Entry
+}
+
+// names of the liked tracks playlists in the exports
+var likesNames = []string{"liked songs", "favorite songs", "favourite songs", "loved tracks"}
+
+// Load reads the playlists from the CSV or JSON export of the other service.
+// Supported are the Exportify and TuneMyMusic CSV, the Apple Music text export,
+// the Spotify account data playlists and library, and the Apple Music library tracks JSON.
+func Load(path string) ([]Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var playlists []Playlist
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".txt":
+		playlists, err = readCSV(decodeText(data), name)
+	case ".json":
+		playlists, err = readJSON(data, name)
+	default:
+		return nil, errors.New("unknown export format, use .csv, .txt or .json file")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range playlists {
+		pl := &playlists[i]
+		for _, likes := range likesNames {
+			if strings.EqualFold(pl.Name, likes) {
+				pl.Likes = true
+			}
+		}
+	}
+	return playlists, nil
+}
+
+// decodeText converts the UTF-16 text used by the Apple Music export to UTF-8 and removes the BOM.
+func decodeText(data []byte) string {
+	if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+		units := make([]uint16, (len(data)-2)/2)
+		for i := range units {
+			units[i] = uint16(data[2+i*2]) | uint16(data[3+i*2])<<8
+		}
+		return string(utf16.Decode(units))
+	}
+	return string(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")))
+}
+
+// CSV columns of the known exports, the first found column is used
+var (
+	titleColumns    = []string{"track name", "song name", "title", "name", "track"}
+	artistColumns   = []string{"artist name(s)", "artist name", "artist names", "artists", "artist"}
+	albumColumns    = []string{"album name", "album title", "album"}
+	durationColumns = []string{"track duration (ms)", "duration (ms)", "duration_ms", "duration", "time"}
+	playlistColumns = []string{"playlist name", "playlist"}
+)
+
+func readCSV(text, name string) ([]Playlist, error) {
+	header, _, _ := strings.Cut(text, "\n")
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	switch {
+	case strings.Contains(header, "\t"):
+		reader.Comma = '\t'
+	case strings.Count(header, ";") > strings.Count(header, ","):
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, column := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	find := func(names []string) int {
+		for _, name := range names {
+			if i, ok := columns[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+
+	titleColumn := find(titleColumns)
+	if titleColumn < 0 {
+		return nil, errors.New("track title column not found")
+	}
+	artistColumn := find(artistColumns)
+	albumColumn := find(albumColumns)
+	durationColumn := find(durationColumns)
+	playlistColumn := find(playlistColumns)
+
+	field := func(record []string, column int) string {
+		if column < 0 || column >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[column])
+	}
+
+	// the rows are grouped by the playlist column keeping the order of the playlists
+	var playlists []Playlist
+	indexes := make(map[string]int)
+	for _, record := range records[1:] {
+		entry := playlistfile.Entry{
+			Title:      field(record, titleColumn),
+			Artists:    splitArtists(field(record, artistColumn)),
+			Album:      field(record, albumColumn),
+			DurationMs: parseDuration(field(record, durationColumn)),
+		}
+		if len(entry.Title) == 0 {
+			continue
+		}
+
+		plName := field(record, playlistColumn)
+		if len(plName) == 0 {
+			plName = name
+		}
+		index, ok := indexes[plName]
+		if !ok {
+			index = len(playlists)
+			indexes[plName] = index
+			playlists = append(playlists, Playlist{Name: plName})
+		}
+		playlists[index].Entries = append(playlists[index].Entries, entry)
+	}
+
+	return playlists, nil
+}
+
+func splitArtists(s string) []string {
+	var artists []string
+	for _, artist := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' }) {
+		if artist = strings.TrimSpace(artist); len(artist) > 0 {
+			artists = append(artists, artist)
+		}
+	}
+	return artists
+}
+
+// parseDuration parses the duration in milliseconds, seconds or the "m:ss" format.
+func parseDuration(s string) int {
+	if minutes, seconds, found := strings.Cut(s, ":"); found {
+		m, _ := strconv.Atoi(minutes)
+		s, _ := strconv.Atoi(seconds)
+		return (m*60 + s) * 1000
+	}
+
+	duration, err := strconv.ParseFloat(s, 64)
+	if err != nil || duration <= 0 {
+		return 0
+	}
+	if duration < 10000 {
+		// no track is shorter than 10 seconds, so it's the duration in seconds
+		duration *= 1000
+	}
+	return int(duration)
+}
+
+type spotifyExport struct {
+	Playlists []struct {
+		Name  string `json:"name"`
+		Items []struct {
+			Track *struct {
+				TrackName  string `json:"trackName"`
+				ArtistName string `json:"artistName"`
+				AlbumName  string `json:"albumName"`
+			} `json:"track"`
+		} `json:"items"`
+	} `json:"playlists"`
+	Tracks []struct {
+		Artist string `json:"artist"`
+		Album  string `json:"album"`
+		Track  string `json:"track"`
+	} `json:"tracks"`
+}
+
+type appleTrack struct {
+	Title    string `json:"Title"`
+	Artist   string `json:"Artist"`
+	Album    string `json:"Album"`
+	Duration int    `json:"Track Duration"`
+}
+
+func readJSON(data []byte, name string) ([]Playlist, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF")))
+
+	if bytes.HasPrefix(data, []byte("[")) {
+		// the Apple Music library is the list of the tracks added to it
+		var tracks []appleTrack
+		err := json.Unmarshal(data, &tracks)
+		if err != nil {
+			return nil, err
+		}
+
+		library := Playlist{Name: name, Likes: true}
+		for _, track := range tracks {
+			if len(track.Title) == 0 {
+				continue
+			}
+			library.Entries = append(library.Entries, playlistfile.Entry{
+				Title:      track.Title,
+				Artists:    splitArtists(track.Artist),
+				Album:      track.Album,
+				DurationMs: track.Duration,
+			})
+		}
+		return []Playlist{library}, nil
+	}
+
+	var export spotifyExport
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return nil, err
+	}
+
+	var playlists []Playlist
+	for _, pl := range export.Playlists {
+		playlist := Playlist{Name: pl.Name}
+		for _, item := range pl.Items {
+			// the podcast episodes and the local files don't have the track
+			if item.Track == nil || len(item.Track.TrackName) == 0 {
+				continue
+			}
+			playlist.Entries = append(playlist.Entries, playlistfile.Entry{
+				Title:   item.Track.TrackName,
+				Artists: splitArtists(item.Track.ArtistName),
+				Album:   item.Track.AlbumName,
+			})
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if len(export.Tracks) > 0 {
+		library := Playlist{Name: name, Likes: true}
+		for _, track := range export.Tracks {
+			library.Entries = append(library.Entries, playlistfile.Entry{
+				Title:   track.Track,
+				Artists: splitArtists(track.Artist),
+				Album:   track.Album,
+			})
+		}
+		playlists = append(playlists, library)
+	}
+
+	if len(playlists) == 0 {
+		return nil, errors.New("no playlists found in the export")
+	}
+	return playlists, nil
+}
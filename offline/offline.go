@@ -0,0 +1,90 @@
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+const fileName = "offline.json"
+
+// Set is the list of playlists kept available offline.
+// It is stored in the config directory, so the playlists are synced again after the restart.
+type Set struct {
+	keys []string
+}
+
+func Path() string {
+	dir := config.Dir()
+	if len(dir) == 0 {
+		return ""
+	}
+	return filepath.Join(dir, fileName)
+}
+
+// Load reads the offline playlists from the file.
+// A missing file is not an error, the empty set is returned.
+func Load() (*Set, error) {
+	s := &Set{}
+
+	path := Path()
+	if len(path) == 0 {
+		return s, errors.New("unable to locate the config directory")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	err = json.Unmarshal(data, &s.keys)
+	return s, err
+}
+
+func (s *Set) Contains(key string) bool {
+	return slices.Contains(s.keys, key)
+}
+
+func (s *Set) Len() int {
+	return len(s.keys)
+}
+
+// Add adds the playlist to the set and writes it to the file.
+func (s *Set) Add(key string) error {
+	if s.Contains(key) {
+		return nil
+	}
+	s.keys = append(s.keys, key)
+	return s.save()
+}
+
+// Remove removes the playlist from the set and writes it to the file.
+func (s *Set) Remove(key string) error {
+	index := slices.Index(s.keys, key)
+	if index < 0 {
+		return nil
+	}
+	s.keys = slices.Delete(s.keys, index, index+1)
+	return s.save()
+}
+
+func (s *Set) save() error {
+	path := Path()
+	if len(path) == 0 {
+		return errors.New("unable to locate the config directory")
+	}
+
+	data, err := json.Marshal(s.keys)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0755)
+}
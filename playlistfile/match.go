@@ -0,0 +1,93 @@
+package playlistfile
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+// maximal duration difference of the matching tracks
+const durationTolerance = 3000
+
+// maximal number of the candidates offered for a review
+const maxCandidates = 5
+
+// Query returns the search request for the entry.
+func (e *Entry) Query() string {
+	if len(e.Artists) == 0 {
+		return e.Title
+	}
+	return e.Artists[0] + " " + e.Title
+}
+
+// Match picks the track matching the entry from the search results.
+// If there is no single matching track, the found candidates are returned for a review.
+func Match(entry *Entry, candidates []api.Track) (*api.Track, []api.Track) {
+	candidates = candidates[:min(len(candidates), maxCandidates)]
+
+	var matches []*api.Track
+	for i := range candidates {
+		if matchTrack(entry, &candidates[i]) {
+			matches = append(matches, &candidates[i])
+		}
+	}
+
+	if len(matches) > 1 && len(entry.Album) > 0 {
+		// the same track is often released in several albums
+		for _, track := range matches {
+			if len(track.Albums) > 0 && Normalize(track.Albums[0].Title) == Normalize(entry.Album) {
+				return track, nil
+			}
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+
+	return nil, candidates
+}
+
+func matchTrack(entry *Entry, track *api.Track) bool {
+	if !track.Available {
+		return false
+	}
+
+	title := Normalize(entry.Title)
+	if title != Normalize(track.Title) && title != Normalize(track.Title+" "+track.Version) {
+		return false
+	}
+
+	if entry.DurationMs > 0 && abs(entry.DurationMs-track.DurationMs) > durationTolerance {
+		return false
+	}
+
+	if len(entry.Artists) == 0 {
+		return true
+	}
+	for _, name := range entry.Artists {
+		for _, artist := range track.Artists {
+			if Normalize(name) == Normalize(artist.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Normalize keeps only the lowercase letters and digits of the string, so the punctuation differences are ignored.
+func Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
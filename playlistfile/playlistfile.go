@@ -0,0 +1,393 @@
+package playlistfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+type Format uint8
+
+const (
+	M3U8 Format = iota
+	XSPF
+	JSON
+)
+
+var ErrUnknownFormat = errors.New("unknown playlist format, use .m3u8, .xspf or .json file")
+
+// FormatOf detects the playlist format from the file extension.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		return M3U8, nil
+	case ".xspf":
+		return XSPF, nil
+	case ".json":
+		return JSON, nil
+	}
+	return 0, ErrUnknownFormat
+}
+
+// Entry is the playlist file track.
+// Id is set only if the entry refers to the Yandex Music track.
+type Entry struct {
+	Id         string
+	Title      string
+	Artists    []string
+	Album      string
+	DurationMs int
+	Location   string
+}
+
+// String returns the entry as "artists - title".
+func (e *Entry) String() string {
+	if len(e.Artists) == 0 {
+		return e.Title
+	}
+	return strings.Join(e.Artists, ", ") + " - " + e.Title
+}
+
+// Save writes the tracks to the playlist file of the format matching the file extension.
+func Save(path, name string, tracks []api.Track) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = Write(file, format, name, tracks)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads the playlist file of the format matching the file extension.
+// The file name is used as the playlist name if the file doesn't have it.
+func Load(path string) (name string, entries []Entry, err error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	name, entries, err = Read(file, format)
+	if len(name) == 0 {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return
+}
+
+func Write(w io.Writer, format Format, name string, tracks []api.Track) error {
+	switch format {
+	case M3U8:
+		return writeM3U(w, name, tracks)
+	case XSPF:
+		return writeXSPF(w, name, tracks)
+	case JSON:
+		return writeJSON(w, name, tracks)
+	}
+	return ErrUnknownFormat
+}
+
+func Read(r io.Reader, format Format) (name string, entries []Entry, err error) {
+	switch format {
+	case M3U8:
+		return readM3U(r)
+	case XSPF:
+		return readXSPF(r)
+	case JSON:
+		return readJSON(r)
+	}
+	return "", nil, ErrUnknownFormat
+}
+
+// trackLocation returns the file path of the local track or the link to the streamed one.
+func trackLocation(track *api.Track) string {
+	if library.IsLocal(track) {
+		return library.FilePath(track)
+	}
+	if link := api.ShareTrackLink(track); len(link) > 0 {
+		return link
+	}
+	return "https://music.yandex.ru/track/" + track.Id
+}
+
+var trackLinkRegexp = regexp.MustCompile(`music\.yandex\.[a-z]+/(?:album/\d+/)?track/(\d+)`)
+
+// trackId returns the track id from the Yandex Music track link.
+func trackId(location string) string {
+	match := trackLinkRegexp.FindStringSubmatch(location)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+func trackArtists(track *api.Track) []string {
+	artists := make([]string, len(track.Artists))
+	for i := range track.Artists {
+		artists[i] = track.Artists[i].Name
+	}
+	return artists
+}
+
+func trackTitle(track *api.Track) string {
+	if len(track.Version) == 0 {
+		return track.Title
+	}
+	return track.Title + " (" + track.Version + ")"
+}
+
+func trackAlbum(track *api.Track) string {
+	if len(track.Albums) == 0 {
+		return ""
+	}
+	return track.Albums[0].Title
+}
+
+func writeM3U(w io.Writer, name string, tracks []api.Track) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "#EXTM3U")
+	if len(name) > 0 {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", name)
+	}
+	for i := range tracks {
+		track := &tracks[i]
+		entry := Entry{Title: trackTitle(track), Artists: trackArtists(track)}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", track.DurationMs/1000, entry.String())
+		fmt.Fprintln(bw, trackLocation(track))
+	}
+	return bw.Flush()
+}
+
+func readM3U(r io.Reader) (name string, entries []Entry, err error) {
+	var info Entry
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case len(line) == 0:
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:duration,artists - title
+			duration, title, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			seconds, _ := strconv.Atoi(strings.TrimSpace(duration))
+			info = parseTitle(title)
+			info.DurationMs = seconds * 1000
+		case strings.HasPrefix(line, "#"):
+		default:
+			entry := info
+			if len(entry.Title) == 0 {
+				// the entries without the info are named by their files
+				base := filepath.Base(line)
+				entry = parseTitle(strings.TrimSuffix(base, filepath.Ext(base)))
+			}
+			entry.Location = line
+			entry.Id = trackId(line)
+			entries = append(entries, entry)
+			info = Entry{}
+		}
+	}
+	err = scanner.Err()
+	return
+}
+
+// parseTitle splits the "artists - title" string.
+func parseTitle(s string) Entry {
+	artists, title, found := strings.Cut(s, " - ")
+	if !found {
+		return Entry{Title: strings.TrimSpace(s)}
+	}
+
+	entry := Entry{Title: strings.TrimSpace(title)}
+	for _, artist := range strings.Split(artists, ",") {
+		if artist = strings.TrimSpace(artist); len(artist) > 0 {
+			entry.Artists = append(entry.Artists, artist)
+		}
+	}
+	return entry
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Version string      `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location,omitempty"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title,omitempty"`
+	Creator    string `xml:"creator,omitempty"`
+	Album      string `xml:"album,omitempty"`
+	Duration   int    `xml:"duration,omitempty"`
+}
+
+func writeXSPF(w io.Writer, name string, tracks []api.Track) error {
+	pl := xspfPlaylist{
+		Xmlns:   "http://xspf.org/ns/0/",
+		Version: "1",
+		Title:   name,
+		Tracks:  make([]xspfTrack, len(tracks)),
+	}
+	for i := range tracks {
+		track := &tracks[i]
+		pl.Tracks[i] = xspfTrack{
+			Location: xspfLocation(track),
+			Title:    trackTitle(track),
+			Creator:  helpers.ArtistList(track.Artists),
+			Album:    trackAlbum(track),
+			Duration: track.DurationMs,
+		}
+		if !library.IsLocal(track) {
+			pl.Tracks[i].Identifier = "https://music.yandex.ru/track/" + track.Id
+		}
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(pl)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// xspfLocation returns the track location as URI required by XSPF.
+func xspfLocation(track *api.Track) string {
+	if !library.IsLocal(track) {
+		return trackLocation(track)
+	}
+	location := url.URL{Scheme: "file", Path: filepath.ToSlash(library.FilePath(track))}
+	if !strings.HasPrefix(location.Path, "/") {
+		// windows drive path
+		location.Path = "/" + location.Path
+	}
+	return location.String()
+}
+
+func readXSPF(r io.Reader) (name string, entries []Entry, err error) {
+	var pl xspfPlaylist
+	err = xml.NewDecoder(r).Decode(&pl)
+	if err != nil {
+		return
+	}
+
+	entries = make([]Entry, len(pl.Tracks))
+	for i, track := range pl.Tracks {
+		entry := parseTitle(track.Creator + " - " + track.Title)
+		if len(track.Creator) == 0 {
+			entry = Entry{Title: strings.TrimSpace(track.Title)}
+		}
+		entry.Album = strings.TrimSpace(track.Album)
+		entry.DurationMs = track.Duration
+		entry.Location = strings.TrimSpace(track.Location)
+		if location, err := url.Parse(entry.Location); err == nil && location.Scheme == "file" {
+			entry.Location = filepath.FromSlash(location.Path)
+		}
+		entry.Id = trackId(track.Identifier)
+		if len(entry.Id) == 0 {
+			entry.Id = trackId(entry.Location)
+		}
+		entries[i] = entry
+	}
+	return pl.Title, entries, nil
+}
+
+type jsonPlaylist struct {
+	Name   string      `json:"name"`
+	Tracks []jsonTrack `json:"tracks"`
+}
+
+type jsonTrack struct {
+	Id         string   `json:"id"`
+	Title      string   `json:"title"`
+	Artists    []string `json:"artists,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	DurationMs int      `json:"durationMs,omitempty"`
+}
+
+func writeJSON(w io.Writer, name string, tracks []api.Track) error {
+	pl := jsonPlaylist{
+		Name:   name,
+		Tracks: make([]jsonTrack, len(tracks)),
+	}
+	for i := range tracks {
+		track := &tracks[i]
+		pl.Tracks[i] = jsonTrack{
+			Id:         track.Id,
+			Title:      trackTitle(track),
+			Artists:    trackArtists(track),
+			Album:      trackAlbum(track),
+			DurationMs: track.DurationMs,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(pl)
+}
+
+func readJSON(r io.Reader) (name string, entries []Entry, err error) {
+	var pl jsonPlaylist
+	err = json.NewDecoder(r).Decode(&pl)
+	if err != nil {
+		return
+	}
+
+	entries = make([]Entry, len(pl.Tracks))
+	for i, track := range pl.Tracks {
+		entries[i] = Entry{
+			Id:         track.Id,
+			Title:      track.Title,
+			Artists:    track.Artists,
+			Album:      track.Album,
+			DurationMs: track.DurationMs,
+		}
+		local := api.Track{Id: track.Id}
+		if library.IsLocal(&local) {
+			entries[i].Id = ""
+			entries[i].Location = library.FilePath(&local)
+		}
+	}
+	return pl.Name, entries, nil
+}
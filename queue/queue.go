@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"slices"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
+
+// Queue holds the tracks that should be played before the rest
+// of the currently playing playlist.
+type Queue struct {
+	tracks []api.Track
+}
+
+func New() *Queue {
+	return &Queue{}
+}
+
+func (q *Queue) Len() int {
+	return len(q.tracks)
+}
+
+func (q *Queue) Tracks() []api.Track {
+	return q.tracks
+}
+
+// Add appends tracks to the end of the queue.
+func (q *Queue) Add(tracks ...api.Track) {
+	q.tracks = append(q.tracks, tracks...)
+}
+
+// PlayNext inserts tracks at the beginning of the queue keeping their order.
+func (q *Queue) PlayNext(tracks ...api.Track) {
+	q.tracks = slices.Insert(q.tracks, 0, tracks...)
+}
+
+// Pop removes the first track from the queue and returns it.
+func (q *Queue) Pop() (api.Track, bool) {
+	if len(q.tracks) == 0 {
+		return api.Track{}, false
+	}
+
+	track := q.tracks[0]
+	q.tracks = slices.Delete(q.tracks, 0, 1)
+	return track, true
+}
+
+// Take removes all tracks up to the index inclusively and returns the last of them.
+func (q *Queue) Take(index int) (api.Track, bool) {
+	if index < 0 || index >= len(q.tracks) {
+		return api.Track{}, false
+	}
+
+	track := q.tracks[index]
+	q.tracks = slices.Delete(q.tracks, 0, index+1)
+	return track, true
+}
+
+func (q *Queue) Remove(index int) bool {
+	if index < 0 || index >= len(q.tracks) {
+		return false
+	}
+
+	q.tracks = slices.Delete(q.tracks, index, index+1)
+	return true
+}
+
+// Move places the track at the index `from` to the index `to`.
+func (q *Queue) Move(from, to int) bool {
+	if from < 0 || from >= len(q.tracks) || to < 0 || to >= len(q.tracks) {
+		return false
+	}
+	if from == to {
+		return true
+	}
+
+	track := q.tracks[from]
+	q.tracks = slices.Delete(q.tracks, from, from+1)
+	q.tracks = slices.Insert(q.tracks, to, track)
+	return true
+}
+
+func (q *Queue) Clear() {
+	q.tracks = nil
+}
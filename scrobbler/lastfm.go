@@ -0,0 +1,173 @@
+package scrobbler
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+// https://www.last.fm/api/scrobbling
+type LastFm struct {
+	client     *http.Client
+	endpoint   string
+	apiKey     string
+	apiSecret  string
+	sessionKey string
+}
+
+type lastFmError struct {
+	Code    int    `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *lastFmError) Error() string {
+	return fmt.Sprintf("last.fm error %d: %s", e.Code, e.Message)
+}
+
+// invalid reports whether the request itself is wrong, so there is no sense to retry it.
+// Authentication errors are retried to keep the scrobbles until the credentials are fixed.
+func (e *lastFmError) invalid() bool {
+	// 6 - invalid parameters
+	return e.Code == 6
+}
+
+func NewLastFm(client *http.Client, conf *config.LastFm) *LastFm {
+	return &LastFm{
+		client:     client,
+		endpoint:   conf.Endpoint,
+		apiKey:     conf.ApiKey,
+		apiSecret:  conf.ApiSecret,
+		sessionKey: conf.SessionKey,
+	}
+}
+
+func (l *LastFm) Name() string {
+	return "lastfm"
+}
+
+// Login obtains the session key with the user credentials.
+func (l *LastFm) Login(username, password string) (string, error) {
+	params := url.Values{
+		"method":   {"auth.getMobileSession"},
+		"username": {username},
+		"password": {password},
+	}
+
+	var resp struct {
+		Session struct {
+			Name string `json:"name"`
+			Key  string `json:"key"`
+		} `json:"session"`
+	}
+
+	err := l.call(params, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	l.sessionKey = resp.Session.Key
+	return resp.Session.Key, nil
+}
+
+func (l *LastFm) NowPlaying(s *Scrobble) error {
+	params := url.Values{
+		"method": {"track.updateNowPlaying"},
+		"sk":     {l.sessionKey},
+		"artist": {s.Artist},
+		"track":  {s.Track},
+	}
+	if len(s.Album) > 0 {
+		params.Set("album", s.Album)
+	}
+	if s.Duration > 0 {
+		params.Set("duration", fmt.Sprint(int(s.Duration.Seconds())))
+	}
+
+	return l.call(params, nil)
+}
+
+func (l *LastFm) Submit(scrobbles []Scrobble) error {
+	params := url.Values{
+		"method": {"track.scrobble"},
+		"sk":     {l.sessionKey},
+	}
+
+	for i, s := range scrobbles {
+		params.Set(fmt.Sprintf("artist[%d]", i), s.Artist)
+		params.Set(fmt.Sprintf("track[%d]", i), s.Track)
+		params.Set(fmt.Sprintf("timestamp[%d]", i), fmt.Sprint(s.Timestamp.Unix()))
+		if len(s.Album) > 0 {
+			params.Set(fmt.Sprintf("album[%d]", i), s.Album)
+		}
+		if s.Duration > 0 {
+			params.Set(fmt.Sprintf("duration[%d]", i), fmt.Sprint(int(s.Duration.Seconds())))
+		}
+	}
+
+	return l.call(params, nil)
+}
+
+// call sends the signed request and decodes the response into the result if it's not nil.
+func (l *LastFm) call(params url.Values, result any) error {
+	params.Set("api_key", l.apiKey)
+	params.Set("api_sig", l.signature(params))
+	params.Set("format", "json")
+
+	resp, err := l.client.PostForm(l.endpoint, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var apiErr lastFmError
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Code != 0 {
+		if apiErr.invalid() {
+			return &PermanentError{&apiErr}
+		}
+		return &apiErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("last.fm responded with status %s", resp.Status)
+	}
+
+	if result != nil {
+		return json.Unmarshal(body, result)
+	}
+
+	return nil
+}
+
+// signature calculates the api_sig parameter: md5 of the sorted parameters concatenated with the secret.
+func (l *LastFm) signature(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "format" && k != "callback" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteString(params.Get(k))
+	}
+	sb.WriteString(l.apiSecret)
+
+	sum := md5.Sum([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
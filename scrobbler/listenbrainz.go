@@ -0,0 +1,135 @@
+package scrobbler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+// https://listenbrainz.readthedocs.io/en/latest/users/api/core.html
+type ListenBrainz struct {
+	client   *http.Client
+	endpoint string
+	token    string
+}
+
+type listenBrainzAdditionalInfo struct {
+	DurationMs       int64  `json:"duration_ms,omitempty"`
+	MediaPlayer      string `json:"media_player"`
+	SubmissionClient string `json:"submission_client"`
+	OriginUrl        string `json:"origin_url,omitempty"`
+}
+
+type listenBrainzMetadata struct {
+	ArtistName     string                     `json:"artist_name"`
+	TrackName      string                     `json:"track_name"`
+	ReleaseName    string                     `json:"release_name,omitempty"`
+	AdditionalInfo listenBrainzAdditionalInfo `json:"additional_info"`
+}
+
+type listenBrainzListen struct {
+	ListenedAt    int64                `json:"listened_at,omitempty"`
+	TrackMetadata listenBrainzMetadata `json:"track_metadata"`
+}
+
+type listenBrainzSubmission struct {
+	ListenType string               `json:"listen_type"`
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+func NewListenBrainz(client *http.Client, conf *config.ListenBrainz) *ListenBrainz {
+	return &ListenBrainz{
+		client:   client,
+		endpoint: strings.TrimSuffix(conf.Endpoint, "/"),
+		token:    conf.Token,
+	}
+}
+
+func (l *ListenBrainz) Name() string {
+	return "listenbrainz"
+}
+
+func (l *ListenBrainz) NowPlaying(s *Scrobble) error {
+	return l.submit("playing_now", []listenBrainzListen{newListen(s, false)})
+}
+
+func (l *ListenBrainz) Submit(scrobbles []Scrobble) error {
+	listens := make([]listenBrainzListen, len(scrobbles))
+	for i := range scrobbles {
+		listens[i] = newListen(&scrobbles[i], true)
+	}
+
+	listenType := "import"
+	if len(listens) == 1 {
+		listenType = "single"
+	}
+
+	return l.submit(listenType, listens)
+}
+
+func newListen(s *Scrobble, withTimestamp bool) listenBrainzListen {
+	listen := listenBrainzListen{
+		TrackMetadata: listenBrainzMetadata{
+			ArtistName:  s.Artist,
+			TrackName:   s.Track,
+			ReleaseName: s.Album,
+			AdditionalInfo: listenBrainzAdditionalInfo{
+				DurationMs:       s.Duration.Milliseconds(),
+				MediaPlayer:      "Yandex Music",
+				SubmissionClient: config.DirName,
+			},
+		},
+	}
+	if len(s.TrackId) > 0 {
+		listen.TrackMetadata.AdditionalInfo.OriginUrl = "https://music.yandex.ru/track/" + s.TrackId
+	}
+	if withTimestamp {
+		listen.ListenedAt = s.Timestamp.Unix()
+	}
+	return listen
+}
+
+func (l *ListenBrainz) submit(listenType string, listens []listenBrainzListen) error {
+	body, err := json.Marshal(listenBrainzSubmission{
+		ListenType: listenType,
+		Payload:    listens,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, l.endpoint+"/1/submit-listens", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Token "+l.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apiErr struct {
+		Code  int    `json:"code"`
+		Error string `json:"error"`
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	json.Unmarshal(respBody, &apiErr)
+
+	err = fmt.Errorf("listenbrainz responded with status %s: %s", resp.Status, apiErr.Error)
+	if resp.StatusCode == http.StatusBadRequest {
+		return &PermanentError{err}
+	}
+	return err
+}
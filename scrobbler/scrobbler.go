@@ -0,0 +1,337 @@
+package scrobbler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/log"
+)
+
+const (
+	// tracks shorter than this are never scrobbled
+	minTrackDuration = 30 * time.Second
+	// the track is scrobbled after a half of it or this time is played
+	maxScrobbleTime = 4 * time.Minute
+	// maximum number of scrobbles sent in one request
+	batchSize = 50
+	// maximum number of scrobbles kept for retrying
+	maxQueueSize   = 10000
+	requestTimeout = 15 * time.Second
+)
+
+type Scrobble struct {
+	TrackId   string        `json:"trackId"`
+	Artist    string        `json:"artist"`
+	Track     string        `json:"track"`
+	Album     string        `json:"album"`
+	Duration  time.Duration `json:"duration"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// PermanentError is returned by the service when the request must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+type service interface {
+	Name() string
+	NowPlaying(s *Scrobble) error
+	Submit(s []Scrobble) error
+}
+
+type serviceQueue struct {
+	service  service
+	pending  []Scrobble
+	flushing bool
+	// number of the oldest scrobbles dropped from the queue while the batch was submitted
+	trimmed int
+}
+
+// Scrobbler submits the played tracks to the configured services.
+// The scrobbles that failed to submit are stored in the config directory
+// and sent again with the next scrobble or at the next start.
+type Scrobbler struct {
+	mu     sync.Mutex
+	queues []*serviceQueue
+}
+
+func New() *Scrobbler {
+	s := &Scrobbler{}
+	client := NewHttpClient()
+
+	conf := config.Current.Scrobbling
+	if conf == nil {
+		return s
+	}
+
+	if conf.LastFm != nil && conf.LastFm.Enabled {
+		if len(conf.LastFm.SessionKey) == 0 {
+			log.Print(log.LVL_WARNIGN, "last.fm scrobbling is enabled but the session key is missing, run the 'scrobbler lastfm-login' command")
+		} else {
+			s.addService(NewLastFm(client, conf.LastFm))
+		}
+	}
+
+	if conf.ListenBrainz != nil && conf.ListenBrainz.Enabled {
+		if len(conf.ListenBrainz.Token) == 0 {
+			log.Print(log.LVL_WARNIGN, "listenbrainz scrobbling is enabled but the token is missing")
+		} else {
+			s.addService(NewListenBrainz(client, conf.ListenBrainz))
+		}
+	}
+
+	return s
+}
+
+func (s *Scrobbler) addService(srv service) {
+	q := &serviceQueue{service: srv}
+
+	pending, err := loadQueue(srv.Name())
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to load %s scrobbles queue: %s", srv.Name(), err)
+	}
+	q.pending = pending
+
+	s.queues = append(s.queues, q)
+}
+
+func (s *Scrobbler) Enabled() bool {
+	return len(s.queues) > 0
+}
+
+// ShouldScrobble reports whether the track was played long enough to be scrobbled.
+func ShouldScrobble(duration, played time.Duration) bool {
+	if duration <= minTrackDuration {
+		return false
+	}
+	return played >= duration/2 || played >= maxScrobbleTime
+}
+
+func NewScrobble(track *api.Track, started time.Time) Scrobble {
+	s := Scrobble{
+		TrackId:   track.Id,
+		Track:     track.Title,
+		Duration:  time.Duration(track.DurationMs) * time.Millisecond,
+		Timestamp: started,
+	}
+	if len(track.Version) > 0 {
+		s.Track += " (" + track.Version + ")"
+	}
+	if len(track.Artists) > 0 {
+		s.Artist = track.Artists[0].Name
+	}
+	if len(track.Albums) > 0 {
+		s.Album = track.Albums[0].Title
+	}
+	return s
+}
+
+// NowPlaying notifies the services about the started track in background.
+func (s *Scrobbler) NowPlaying(track *api.Track) {
+	if !s.Enabled() || len(track.Artists) == 0 {
+		return
+	}
+
+	scrobble := NewScrobble(track, time.Now())
+	for _, q := range s.queues {
+		go func(srv service) {
+			err := srv.NowPlaying(&scrobble)
+			if err != nil {
+				log.Print(log.LVL_WARNIGN, "failed to update %s now playing: %s", srv.Name(), err)
+			}
+		}(q.service)
+	}
+}
+
+// Scrobble adds the played track to the queue and submits the queue in background.
+func (s *Scrobbler) Scrobble(track *api.Track, started time.Time, played time.Duration) {
+	if !s.Enabled() || len(track.Artists) == 0 {
+		return
+	}
+
+	scrobble := NewScrobble(track, started)
+	if !ShouldScrobble(scrobble.Duration, played) {
+		return
+	}
+
+	s.mu.Lock()
+	for _, q := range s.queues {
+		q.pending = append(q.pending, scrobble)
+		if len(q.pending) > maxQueueSize {
+			q.trimmed += len(q.pending) - maxQueueSize
+			q.pending = q.pending[len(q.pending)-maxQueueSize:]
+		}
+		s.save(q)
+	}
+	s.mu.Unlock()
+
+	s.Flush()
+}
+
+// Flush submits all queued scrobbles in background.
+func (s *Scrobbler) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, q := range s.queues {
+		if q.flushing || len(q.pending) == 0 {
+			continue
+		}
+		q.flushing = true
+		go s.flush(q)
+	}
+}
+
+// FlushSync submits all queued scrobbles and waits for the submission to finish.
+func (s *Scrobbler) FlushSync() {
+	var wg sync.WaitGroup
+
+	s.mu.Lock()
+	for _, q := range s.queues {
+		if q.flushing || len(q.pending) == 0 {
+			continue
+		}
+		q.flushing = true
+		wg.Add(1)
+		go func(q *serviceQueue) {
+			s.flush(q)
+			wg.Done()
+		}(q)
+	}
+	s.mu.Unlock()
+
+	wg.Wait()
+}
+
+// Pending returns the number of the queued scrobbles of each service.
+func (s *Scrobbler) Pending() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make(map[string]int, len(s.queues))
+	for _, q := range s.queues {
+		pending[q.service.Name()] = len(q.pending)
+	}
+	return pending
+}
+
+func (s *Scrobbler) flush(q *serviceQueue) {
+	for {
+		s.mu.Lock()
+		if len(q.pending) == 0 {
+			q.flushing = false
+			s.mu.Unlock()
+			return
+		}
+		batch := make([]Scrobble, min(len(q.pending), batchSize))
+		copy(batch, q.pending)
+		q.trimmed = 0
+		s.mu.Unlock()
+
+		err := q.service.Submit(batch)
+
+		var permErr *PermanentError
+		if err != nil && !errors.As(err, &permErr) {
+			log.Print(log.LVL_WARNIGN, "failed to submit %d scrobbles to %s, will retry later: %s", len(batch), q.service.Name(), err)
+			s.mu.Lock()
+			q.flushing = false
+			s.mu.Unlock()
+			return
+		}
+		if err != nil {
+			log.Print(log.LVL_ERROR, "%s rejected %d scrobbles: %s", q.service.Name(), len(batch), err)
+		} else {
+			log.Print(log.LVL_INFO, "%d scrobbles submitted to %s", len(batch), q.service.Name())
+		}
+
+		s.mu.Lock()
+		// the trimmed scrobbles were the head of the batch, so only the rest of it is still queued
+		q.pending = q.pending[max(len(batch)-q.trimmed, 0):]
+		q.trimmed = 0
+		s.save(q)
+		s.mu.Unlock()
+	}
+}
+
+func (s *Scrobbler) save(q *serviceQueue) {
+	err := saveQueue(q.service.Name(), q.pending)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to save %s scrobbles queue: %s", q.service.Name(), err)
+	}
+}
+
+func queuePath(name string) (string, error) {
+	dir := config.Dir()
+	if len(dir) == 0 {
+		return "", errors.New("unable to locate the config directory")
+	}
+	return filepath.Join(dir, "scrobbles-"+name+".json"), nil
+}
+
+func loadQueue(name string) ([]Scrobble, error) {
+	path, err := queuePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pending []Scrobble
+	err = json.Unmarshal(content, &pending)
+	return pending, err
+}
+
+func saveQueue(name string, pending []Scrobble) error {
+	path, err := queuePath(name)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		err = os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	content, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0755)
+}
+
+func NewHttpClient() *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+
+	if len(config.Current.Proxy) > 0 {
+		proxyUrl, err := url.Parse(config.Current.Proxy)
+		if err == nil {
+			transport.Proxy = http.ProxyURL(proxyUrl)
+		}
+	}
+
+	return &http.Client{Transport: transport, Timeout: requestTimeout}
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+type jsonItem struct {
+	Id      string  `json:"id"`
+	Name    string  `json:"name"`
+	Plays   int     `json:"plays"`
+	Seconds float64 `json:"seconds"`
+}
+
+type jsonReport struct {
+	Period  string     `json:"period"`
+	From    *time.Time `json:"from,omitempty"`
+	To      time.Time  `json:"to"`
+	Plays   int        `json:"plays"`
+	Seconds float64    `json:"seconds"`
+	Tracks  []jsonItem `json:"tracks"`
+	Artists []jsonItem `json:"artists"`
+	Albums  []jsonItem `json:"albums"`
+	Genres  []jsonItem `json:"genres"`
+}
+
+func toJsonItems(items []Item) []jsonItem {
+	res := make([]jsonItem, len(items))
+	for i, item := range items {
+		res[i] = jsonItem{
+			Id:      item.Id,
+			Name:    item.Name,
+			Plays:   item.Plays,
+			Seconds: item.Time.Seconds(),
+		}
+	}
+	return res
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	rep := jsonReport{
+		Period:  r.Period.Name,
+		To:      r.Period.To,
+		Plays:   r.Plays,
+		Seconds: r.Time.Seconds(),
+		Tracks:  toJsonItems(r.Tracks),
+		Artists: toJsonItems(r.Artists),
+		Albums:  toJsonItems(r.Albums),
+		Genres:  toJsonItems(r.Genres),
+	}
+	if !r.Period.From.IsZero() {
+		rep.From = &r.Period.From
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// WriteCSV writes all categories in one table with the category in the first column.
+func (r *Report) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.Write([]string{"category", "rank", "id", "name", "plays", "seconds"})
+
+	categories := []struct {
+		name  string
+		items []Item
+	}{
+		{"track", r.Tracks},
+		{"artist", r.Artists},
+		{"album", r.Albums},
+		{"genre", r.Genres},
+	}
+
+	for _, c := range categories {
+		for i, item := range c.items {
+			csvWriter.Write([]string{
+				c.name,
+				fmt.Sprint(i + 1),
+				item.Id,
+				item.Name,
+				fmt.Sprint(item.Plays),
+				fmt.Sprintf("%.0f", item.Time.Seconds()),
+			})
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
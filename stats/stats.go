@@ -0,0 +1,237 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/history"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+// the playback counts as a play if it lasts at least this long or a half of the track
+const minPlayTime = 30 * time.Second
+
+type Period struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+var PeriodNames = []string{"week", "month", "year", "all"}
+
+// ParsePeriod returns the period by its name.
+// The name is one of the PeriodNames or a year number, e.g. "2024".
+// The "year" period is the current calendar year.
+func ParsePeriod(name string, now time.Time) (Period, error) {
+	p := Period{Name: name, To: now}
+
+	switch name {
+	case "week":
+		p.From = now.AddDate(0, 0, -7)
+	case "month":
+		p.From = now.AddDate(0, -1, 0)
+	case "year":
+		p.From = time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	case "all":
+	default:
+		year, err := strconv.Atoi(name)
+		if err != nil || year < 1970 || year > now.Year() {
+			return p, fmt.Errorf("unknown period '%s', expected one of %s or a year", name, strings.Join(PeriodNames, ", "))
+		}
+		p.From = time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+		p.To = p.From.AddDate(1, 0, 0)
+	}
+
+	return p, nil
+}
+
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+type Item struct {
+	Id    string
+	Name  string
+	Plays int
+	Time  time.Duration
+}
+
+type Report struct {
+	Period  Period
+	Plays   int
+	Time    time.Duration
+	Tracks  []Item
+	Artists []Item
+	Albums  []Item
+	Genres  []Item
+}
+
+type counter struct {
+	index map[string]int
+	items []Item
+}
+
+// add counts the playback of the item identified by the key.
+// The key is the item id, except for the items without the id that are keyed by their names.
+func (c *counter) add(key, id, name string, played time.Duration, counted bool) {
+	if c.index == nil {
+		c.index = make(map[string]int)
+	}
+
+	i, ok := c.index[key]
+	if !ok {
+		i = len(c.items)
+		c.index[key] = i
+		c.items = append(c.items, Item{Id: id, Name: name})
+	}
+
+	c.items[i].Time += played
+	if counted {
+		c.items[i].Plays++
+	}
+}
+
+// Compute aggregates the playback history over the period.
+func Compute(period Period) (*Report, error) {
+	var tracks, artists, albums, genres counter
+	r := &Report{Period: period}
+
+	err := history.Read(func(e history.Entry) bool {
+		if !period.Contains(e.Time) {
+			return true
+		}
+
+		played := time.Duration(e.Played * float64(time.Second))
+		counted := isPlay(&e.Track, played)
+
+		r.Time += played
+		if counted {
+			r.Plays++
+		}
+
+		track := &e.Track
+		tracks.add(track.Id, track.Id, trackName(track), played, counted)
+		for i := range track.Artists {
+			artist := &track.Artists[i]
+			artists.add(artistKey(artist), itemId(artist.Id), artist.Name, played, counted)
+		}
+		if len(track.Albums) > 0 {
+			album := &track.Albums[0]
+			albums.add(albumKey(album, track), itemId(album.Id), album.Title, played, counted)
+			if len(album.Genre) > 0 {
+				genres.add(album.Genre, album.Genre, album.Genre, played, counted)
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.Tracks = tracks.items
+	r.Artists = artists.items
+	r.Albums = albums.items
+	r.Genres = genres.items
+	r.Sort(false)
+
+	return r, nil
+}
+
+// Sort orders the items by the play count or by the listening time.
+func (r *Report) Sort(byTime bool) {
+	compare := func(a, b Item) int {
+		byPlays := cmp.Compare(b.Plays, a.Plays)
+		byDuration := cmp.Compare(b.Time, a.Time)
+		if byTime {
+			byPlays, byDuration = byDuration, byPlays
+		}
+		if byPlays != 0 {
+			return byPlays
+		}
+		if byDuration != 0 {
+			return byDuration
+		}
+		return strings.Compare(a.Name, b.Name)
+	}
+
+	slices.SortStableFunc(r.Tracks, compare)
+	slices.SortStableFunc(r.Artists, compare)
+	slices.SortStableFunc(r.Albums, compare)
+	slices.SortStableFunc(r.Genres, compare)
+}
+
+// Top returns the first n items of each category.
+func (r *Report) Top(n int) *Report {
+	top := *r
+	top.Tracks = r.Tracks[:min(n, len(r.Tracks))]
+	top.Artists = r.Artists[:min(n, len(r.Artists))]
+	top.Albums = r.Albums[:min(n, len(r.Albums))]
+	top.Genres = r.Genres[:min(n, len(r.Genres))]
+	return &top
+}
+
+func isPlay(track *api.Track, played time.Duration) bool {
+	if played >= minPlayTime {
+		return true
+	}
+	return track.DurationMs > 0 && played >= time.Duration(track.DurationMs)*time.Millisecond/2
+}
+
+// itemId returns the id of the artist or album, the tracks restored from the ID3 tags have no ids.
+func itemId(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+	return fmt.Sprint(id)
+}
+
+// artistKey identifies the artist by the id or by the name if the id is unknown.
+func artistKey(artist *api.Artist) string {
+	if artist.Id != 0 {
+		return fmt.Sprint(artist.Id)
+	}
+	return "name:" + normalizeName(artist.Name)
+}
+
+// albumKey identifies the album by the id or by the title and the artist if the id is unknown,
+// as the different artists often have the albums with the same title.
+func albumKey(album *api.Album, track *api.Track) string {
+	if album.Id != 0 {
+		return fmt.Sprint(album.Id)
+	}
+	key := "name:" + normalizeName(album.Title)
+	if len(track.Artists) > 0 {
+		key += "\x00" + normalizeName(track.Artists[0].Name)
+	}
+	return key
+}
+
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+func trackName(track *api.Track) string {
+	name := track.Title
+	if len(track.Version) > 0 {
+		name += " (" + track.Version + ")"
+	}
+	if len(track.Artists) > 0 {
+		name += " - " + helpers.ArtistList(track.Artists)
+	}
+	return name
+}
+
+// FormatDuration returns the duration as hours and minutes, e.g. "12h 05m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -8,8 +8,10 @@ import (
 type helpKeyMap struct {
 	apply  key.Binding
 	cancel key.Binding
+	toggle key.Binding
 
-	Action string
+	Action       string
+	ToggleAction string
 }
 
 func newHelpMap() *helpKeyMap {
@@ -23,12 +25,20 @@ func newHelpMap() *helpKeyMap {
 			controls.Cancel.Binding(),
 			controls.Cancel.Help("cancel"),
 		),
+		toggle: key.NewBinding(
+			controls.Toggle.Binding(),
+			controls.Toggle.Help("toggle"),
+		),
 	}
 }
 
 func (k *helpKeyMap) ShortHelp() []key.Binding {
 	k.apply.SetHelp(k.apply.Help().Key, k.Action)
-	return []key.Binding{k.apply, k.cancel}
+	if len(k.ToggleAction) == 0 {
+		return []key.Binding{k.apply, k.cancel}
+	}
+	k.toggle.SetHelp(k.toggle.Help().Key, k.ToggleAction)
+	return []key.Binding{k.apply, k.toggle, k.cancel}
 }
 
 func (k *helpKeyMap) FullHelp() [][]key.Binding {
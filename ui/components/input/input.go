@@ -15,6 +15,7 @@ type Control uint
 const (
 	APPLY Control = iota
 	CANCEL
+	TOGGLE
 )
 
 type Model struct {
@@ -26,6 +27,10 @@ type Model struct {
 
 	Title  string
 	Action string
+	// Info is displayed under the input
+	Info string
+	// ToggleAction enables the toggle control if not empty
+	ToggleAction string
 }
 
 func New() *Model {
@@ -45,10 +50,18 @@ func (m *Model) Init() tea.Cmd {
 
 func (m *Model) View() string {
 	m.helpKeys.Action = m.Action
+	m.helpKeys.ToggleAction = m.ToggleAction
+
+	box := m.input.View()
+	if len(m.Info) > 0 {
+		info := lipgloss.NewStyle().Width(m.input.Width).Render(m.Info)
+		box = lipgloss.JoinVertical(lipgloss.Left, box, "", info)
+	}
+
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		style.DialogTitleStyle.Render(m.Title),
-		style.DialogBoxStyle.Render(m.input.View()),
+		style.DialogBoxStyle.Render(box),
 		style.DialogHelpStyle.Render(m.help.View(m.helpKeys)),
 	)
 }
@@ -72,6 +85,8 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 		case controls.Cancel.Contains(keypress):
 			cmds = append(cmds, model.Cmd(CANCEL))
 			m.input.Reset()
+		case len(m.ToggleAction) > 0 && controls.Toggle.Contains(keypress):
+			cmds = append(cmds, model.Cmd(TOGGLE))
 		default:
 			m.input, cmd = m.input.Update(msg)
 			cmds = append(cmds, cmd)
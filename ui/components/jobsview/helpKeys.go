@@ -0,0 +1,48 @@
+package jobsview
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+type helpKeyMap struct {
+	CursorUp      key.Binding
+	CursorDown    key.Binding
+	Pause         key.Binding
+	PauseAll      key.Binding
+	Cancel        key.Binding
+	Retry         key.Binding
+	Clear         key.Binding
+	ShowHelp      key.Binding
+	CloseHelp     key.Binding
+	HideTracklist key.Binding
+}
+
+func newHelpMap() *helpKeyMap {
+	controls := config.Current.Controls
+	return &helpKeyMap{
+		CursorUp:      key.NewBinding(controls.CursorUp.Binding(), controls.CursorUp.Help("up")),
+		CursorDown:    key.NewBinding(controls.CursorDown.Binding(), controls.CursorDown.Help("down")),
+		Pause:         key.NewBinding(controls.DownloadsPause.Binding(), controls.DownloadsPause.Help("pause/resume")),
+		PauseAll:      key.NewBinding(controls.DownloadsPauseAll.Binding(), controls.DownloadsPauseAll.Help("pause/resume all")),
+		Cancel:        key.NewBinding(controls.DownloadsCancel.Binding(), controls.DownloadsCancel.Help("cancel")),
+		Retry:         key.NewBinding(controls.DownloadsRetry.Binding(), controls.DownloadsRetry.Help("retry")),
+		Clear:         key.NewBinding(controls.DownloadsClear.Binding(), controls.DownloadsClear.Help("clear finished")),
+		HideTracklist: key.NewBinding(controls.TracksHide.Binding(), controls.TracksHide.Help("hide")),
+		ShowHelp:      key.NewBinding(controls.ShowAllKeys.Binding(), controls.ShowAllKeys.Help("show keys")),
+		CloseHelp:     key.NewBinding(controls.ShowAllKeys.Binding(), controls.ShowAllKeys.Help("hide keys")),
+	}
+}
+
+func (k helpKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Pause, k.Cancel, k.Retry, k.ShowHelp}
+}
+
+func (k helpKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.CursorUp, k.CursorDown},
+		{k.Pause, k.PauseAll},
+		{k.Cancel, k.Retry, k.Clear},
+		{k.HideTracklist, k.CloseHelp},
+	}
+}
@@ -0,0 +1,202 @@
+package jobsview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/downloader"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+	"github.com/dece2183/yamusic-tui/ui/model"
+	"github.com/dece2183/yamusic-tui/ui/style"
+)
+
+type Control uint
+
+const (
+	PAUSE Control = iota
+	PAUSE_ALL
+	CANCEL
+	RETRY
+	CLEAR
+)
+
+type Model struct {
+	help          help.Model
+	helpMap       *helpKeyMap
+	width, height int
+	jobs          []downloader.Job
+	paused        bool
+	cursor        int
+	offset        int
+}
+
+func New() *Model {
+	m := &Model{
+		help:    help.New(),
+		helpMap: newHelpMap(),
+	}
+
+	m.help.Ellipsis = "…"
+	m.help.Styles.FullDesc = m.help.Styles.FullDesc.PaddingRight(1)
+
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) View() string {
+	helpView := m.help.View(m.helpMap)
+	contentWidth := m.width - 6
+	contentHeight := m.height - lipgloss.Height(helpView) - 5
+
+	content := lipgloss.NewStyle().Width(contentWidth).Height(contentHeight).MaxHeight(contentHeight).Render(m.renderJobs(contentWidth, contentHeight))
+	return style.TrackBoxStyle.Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, content, "", helpView))
+}
+
+func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := message.(type) {
+	case tea.KeyMsg:
+		controls := config.Current.Controls
+		keypress := msg.String()
+
+		switch {
+		case controls.CursorUp.Contains(keypress):
+			m.cursor = max(m.cursor-1, 0)
+		case controls.CursorDown.Contains(keypress):
+			m.cursor = max(min(m.cursor+1, len(m.jobs)-1), 0)
+		case controls.DownloadsPause.Contains(keypress):
+			cmd = model.Cmd(PAUSE)
+		case controls.DownloadsPauseAll.Contains(keypress):
+			cmd = model.Cmd(PAUSE_ALL)
+		case controls.DownloadsCancel.Contains(keypress):
+			cmd = model.Cmd(CANCEL)
+		case controls.DownloadsRetry.Contains(keypress):
+			cmd = model.Cmd(RETRY)
+		case controls.DownloadsClear.Contains(keypress):
+			cmd = model.Cmd(CLEAR)
+		case controls.ShowAllKeys.Contains(keypress):
+			m.help.ShowAll = !m.help.ShowAll
+		}
+	}
+
+	return m, cmd
+}
+
+// SetJobs displays the jobs keeping the cursor on the same job if it's still listed.
+func (m *Model) SetJobs(jobs []downloader.Job, paused bool) {
+	if selected, ok := m.SelectedJob(); ok {
+		for i := range jobs {
+			if jobs[i].Id == selected.Id {
+				m.cursor = i
+				break
+			}
+		}
+	}
+
+	m.jobs = jobs
+	m.paused = paused
+	m.cursor = max(min(m.cursor, len(jobs)-1), 0)
+}
+
+func (m *Model) SelectedJob() (downloader.Job, bool) {
+	if m.cursor >= len(m.jobs) {
+		return downloader.Job{}, false
+	}
+	return m.jobs[m.cursor], true
+}
+
+func (m *Model) SetWidth(width int) {
+	m.width = width
+	m.help.Width = width - 6
+}
+
+func (m *Model) SetHeight(height int) {
+	m.height = height
+}
+
+func (m *Model) renderJobs(width, height int) string {
+	var active, queued, failed int
+	for _, job := range m.jobs {
+		switch job.State {
+		case downloader.ACTIVE:
+			active++
+		case downloader.QUEUED:
+			queued++
+		case downloader.FAILED:
+			failed++
+		}
+	}
+
+	summary := fmt.Sprintf("%d active, %d queued, %d failed", active, queued, failed)
+	if m.paused {
+		summary += ", paused"
+	}
+
+	lines := []string{
+		style.TrackListTitleStyle.Render("Downloads"),
+		style.TrackArtistStyle.Render(summary),
+		"",
+	}
+
+	if len(m.jobs) == 0 {
+		lines = append(lines, style.TrackVersionStyle.Render("  no downloads"))
+		return strings.Join(lines, "\n")
+	}
+
+	// keep the cursor in the visible rows
+	rows := max(height-len(lines), 1)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	m.offset = max(min(m.offset, len(m.jobs)-rows), 0)
+
+	for i := m.offset; i < min(m.offset+rows, len(m.jobs)); i++ {
+		lines = append(lines, m.renderJob(&m.jobs[i], i == m.cursor, width))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) renderJob(job *downloader.Job, selected bool, width int) string {
+	var info string
+	switch job.State {
+	case downloader.ACTIVE:
+		if job.Size > 0 {
+			info = fmt.Sprintf("%3d%%", job.Read*100/job.Size)
+		} else {
+			info = job.State.String()
+		}
+	case downloader.FAILED:
+		info = style.ErrorTextStyle.Render(job.State.String())
+	default:
+		info = job.State.String()
+	}
+	if job.Tries > 0 && !job.State.Finished() {
+		info = fmt.Sprintf("retry %d, %s", job.Tries, info)
+	}
+	info = fmt.Sprintf(" %s %s", style.TrackVersionStyle.Render(job.Task), info)
+
+	name := job.Track.Title + " - " + helpers.ArtistList(job.Track.Artists)
+	if selected {
+		name = style.AccentTextStyle.Render("> " + name)
+	} else {
+		name = "  " + name
+	}
+
+	nameWidth := max(width-lipgloss.Width(info), 1)
+	if lipgloss.Width(name) > nameWidth {
+		name = lipgloss.NewStyle().MaxWidth(nameWidth-1).Render(name) + "…"
+	}
+	gap := strings.Repeat(" ", max(width-lipgloss.Width(name)-lipgloss.Width(info), 0))
+	return name + gap + info
+}
@@ -9,8 +9,17 @@ type helpKeyMap struct {
 	CursorUp      key.Binding
 	CursorDown    key.Binding
 	Rename        key.Binding
+	Edit          key.Binding
+	OpenUser      key.Binding
+	Pin           key.Binding
+	Offline       key.Binding
+	Export        key.Binding
+	Import        key.Binding
 	HidePlaylists key.Binding
 	Renamable     bool
+	Pinnable      bool
+	Offlinable    bool
+	Exportable    bool
 }
 
 func newHelpMap() *helpKeyMap {
@@ -19,6 +28,12 @@ func newHelpMap() *helpKeyMap {
 		CursorUp:      key.NewBinding(controls.PlaylistsUp.Binding(), controls.PlaylistsUp.Help("up")),
 		CursorDown:    key.NewBinding(controls.PlaylistsDown.Binding(), controls.PlaylistsDown.Help("down")),
 		Rename:        key.NewBinding(controls.PlaylistsRename.Binding(), controls.PlaylistsRename.Help("rename")),
+		Edit:          key.NewBinding(controls.PlaylistsEdit.Binding(), controls.PlaylistsEdit.Help("edit")),
+		OpenUser:      key.NewBinding(controls.PlaylistsOpenUser.Binding(), controls.PlaylistsOpenUser.Help("open user")),
+		Pin:           key.NewBinding(controls.PlaylistsPin.Binding(), controls.PlaylistsPin.Help("pin/unpin")),
+		Offline:       key.NewBinding(controls.PlaylistsOffline.Binding(), controls.PlaylistsOffline.Help("offline")),
+		Export:        key.NewBinding(controls.PlaylistsExport.Binding(), controls.PlaylistsExport.Help("export")),
+		Import:        key.NewBinding(controls.PlaylistsImport.Binding(), controls.PlaylistsImport.Help("import")),
 		HidePlaylists: key.NewBinding(controls.PlaylistsHide.Binding(), controls.PlaylistsHide.Help("hide")),
 	}
 }
@@ -33,10 +48,24 @@ func (k helpKeyMap) FullHelp() [][]key.Binding {
 	}
 
 	if k.Renamable {
-		bindings = append(bindings, []key.Binding{k.Rename})
+		bindings = append(bindings, []key.Binding{k.Rename, k.Edit})
 	}
 
-	bindings = append(bindings, []key.Binding{k.HidePlaylists})
+	if k.Pinnable {
+		bindings = append(bindings, []key.Binding{k.Pin})
+	}
+
+	if k.Offlinable {
+		bindings = append(bindings, []key.Binding{k.Offline})
+	}
+
+	if k.Exportable {
+		bindings = append(bindings, []key.Binding{k.Export, k.Import})
+	} else {
+		bindings = append(bindings, []key.Binding{k.Import})
+	}
+
+	bindings = append(bindings, []key.Binding{k.OpenUser, k.HidePlaylists})
 
 	return bindings
 }
@@ -1,43 +1,76 @@
 package playlist
 
-import "github.com/dece2183/yamusic-tui/api"
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/dece2183/yamusic-tui/api"
+)
 
 type Item struct {
-	Uid uint64
+	// owner and kind of the other user playlist
+	Uid        uint64
+	RemoteKind uint64
 
 	Name         string
 	Kind         uint64
 	Revision     int
 	StationId    api.StationId
+	Seed         *api.StationId
 	SessionBatch string
 	SessionId    string
+	Feedbacks    []*api.RotorFeedback
 	Active       bool
 	Subitem      bool
 	Rotor        bool
+	Refilling    bool
+	Shuffled     bool
+	Offline      bool
 
 	Tracks        []api.Track
 	CurrentTrack  int
 	SelectedTrack int
+
+	// playback order of the shuffled playlist
+	order    []int
+	orderPos int
+	smart    bool
 }
 
 func (i *Item) FilterValue() string {
 	return i.Name
 }
 
+// IsRemote reports whether the playlist belongs to the other user.
+func (i *Item) IsRemote() bool {
+	return i.Uid != 0
+}
+
 func (i *Item) IsSame(other *Item) bool {
 	return i.Kind == other.Kind && i.Name == other.Name
 }
 
 func (pl *Item) AddTrack(track *api.Track) {
 	pl.Tracks = append([]api.Track{*track}, pl.Tracks...)
-	pl.SelectedTrack++
+	if len(pl.Tracks) > 1 {
+		pl.SelectedTrack++
+	}
 	if pl.CurrentTrack < len(pl.Tracks)-1 {
 		pl.CurrentTrack++
 	}
+	if pl.Shuffled {
+		for i := range pl.order {
+			pl.order[i]++
+		}
+		pl.orderInsert(0)
+	}
 }
 
 func (pl *Item) AddTrackToEnd(track *api.Track) {
 	pl.Tracks = append(pl.Tracks, *track)
+	if pl.Shuffled {
+		pl.orderInsert(len(pl.Tracks) - 1)
+	}
 }
 
 func (pl *Item) RemoveTrack(trackId string) int {
@@ -49,6 +82,10 @@ func (pl *Item) RemoveTrack(trackId string) int {
 				pl.Tracks = pl.Tracks[:i]
 			}
 
+			if pl.Shuffled {
+				pl.orderRemove(i)
+			}
+
 			if len(pl.Tracks) == 0 {
 				pl.SelectedTrack = 0
 				pl.CurrentTrack = 0
@@ -71,3 +108,228 @@ func (pl *Item) RemoveTrack(trackId string) int {
 	}
 	return -1
 }
+
+// Rearrange replaces the tracks with the old tracks at the indexes, the missing indexes are removed.
+// The current track and the shuffled playback order follow the tracks.
+func (pl *Item) Rearrange(indexes []int) {
+	newIndex := make([]int, len(pl.Tracks))
+	for i := range newIndex {
+		newIndex[i] = -1
+	}
+
+	tracks := make([]api.Track, len(indexes))
+	for i, old := range indexes {
+		tracks[i] = pl.Tracks[old]
+		newIndex[old] = i
+	}
+
+	if pl.CurrentTrack < len(newIndex) && newIndex[pl.CurrentTrack] >= 0 {
+		pl.CurrentTrack = newIndex[pl.CurrentTrack]
+	} else {
+		pl.CurrentTrack = len(tracks)
+	}
+	if pl.SelectedTrack < len(newIndex) && newIndex[pl.SelectedTrack] >= 0 {
+		pl.SelectedTrack = newIndex[pl.SelectedTrack]
+	} else {
+		pl.SelectedTrack = min(pl.SelectedTrack, max(len(tracks)-1, 0))
+	}
+
+	if pl.Shuffled && len(pl.order) == len(pl.Tracks) {
+		order := make([]int, 0, len(tracks))
+		orderPos := pl.orderPos
+		for pos, old := range pl.order {
+			if newIndex[old] >= 0 {
+				order = append(order, newIndex[old])
+			} else if pos <= pl.orderPos {
+				orderPos--
+			}
+		}
+		pl.order = order
+		pl.orderPos = orderPos
+	}
+
+	pl.Tracks = tracks
+}
+
+// Upcoming returns the number of tracks after the current one.
+func (pl *Item) Upcoming() int {
+	return max(len(pl.Tracks)-1-pl.CurrentTrack, 0)
+}
+
+// Shuffle enables the shuffled playback order starting from the current track.
+// The tracks themselves are left in place, so the original order can be restored with Unshuffle.
+// The smart shuffle tries to avoid playing tracks of the same artist back to back.
+func (pl *Item) Shuffle(smart bool) {
+	pl.Shuffled = true
+	pl.smart = smart
+	pl.order = make([]int, 0, len(pl.Tracks))
+	pl.orderPos = 0
+
+	if len(pl.Tracks) == 0 {
+		return
+	}
+
+	first := min(max(pl.CurrentTrack, 0), len(pl.Tracks)-1)
+	pl.order = append(pl.order, first)
+	for _, i := range rand.Perm(len(pl.Tracks)) {
+		if i != first {
+			pl.order = append(pl.order, i)
+		}
+	}
+
+	if smart {
+		pl.spreadArtists()
+	}
+}
+
+// Unshuffle restores the original playback order.
+func (pl *Item) Unshuffle() {
+	pl.Shuffled = false
+	pl.order = nil
+	pl.orderPos = 0
+}
+
+// Next moves to the next track in the playback order and returns its index.
+// It returns -1 when the end of the playlist is reached.
+func (pl *Item) Next() int {
+	if !pl.Shuffled {
+		if pl.CurrentTrack+1 >= len(pl.Tracks) {
+			return -1
+		}
+		pl.CurrentTrack++
+		return pl.CurrentTrack
+	}
+
+	pl.checkOrder()
+	if pl.orderPos+1 >= len(pl.order) {
+		return -1
+	}
+	pl.orderPos++
+	pl.CurrentTrack = pl.order[pl.orderPos]
+	return pl.CurrentTrack
+}
+
+// Prev moves to the previously played track in the playback order and returns its index.
+// It returns -1 when the beginning of the playlist is reached.
+func (pl *Item) Prev() int {
+	if !pl.Shuffled {
+		if pl.CurrentTrack <= 0 || len(pl.Tracks) == 0 {
+			return -1
+		}
+		pl.CurrentTrack = min(pl.CurrentTrack, len(pl.Tracks)) - 1
+		return pl.CurrentTrack
+	}
+
+	pl.checkOrder()
+	if pl.orderPos <= 0 {
+		return -1
+	}
+	pl.orderPos--
+	pl.CurrentTrack = pl.order[pl.orderPos]
+	return pl.CurrentTrack
+}
+
+// Restart moves to the beginning of the playback order and returns the index of the first track.
+// The shuffled playlist gets a new order.
+func (pl *Item) Restart() int {
+	if len(pl.Tracks) == 0 {
+		return -1
+	}
+
+	if !pl.Shuffled {
+		pl.CurrentTrack = 0
+		return pl.CurrentTrack
+	}
+
+	pl.CurrentTrack = rand.Intn(len(pl.Tracks))
+	pl.Shuffle(pl.smart)
+	return pl.CurrentTrack
+}
+
+// Select makes the track at the index current.
+// In the shuffled playlist the track is placed right after the current one in the playback order,
+// so the previously played tracks are kept in the history.
+func (pl *Item) Select(index int) {
+	if !pl.Shuffled || index == pl.CurrentTrack {
+		pl.CurrentTrack = index
+		return
+	}
+
+	pl.checkOrder()
+	pos := slices.Index(pl.order, index)
+	if pos < 0 {
+		pl.CurrentTrack = index
+		pl.Shuffle(pl.smart)
+		return
+	}
+
+	pl.order = slices.Delete(pl.order, pos, pos+1)
+	if pos <= pl.orderPos {
+		pl.orderPos--
+	}
+	pl.orderPos = min(pl.orderPos+1, len(pl.order))
+	pl.order = slices.Insert(pl.order, pl.orderPos, index)
+	pl.CurrentTrack = index
+}
+
+// checkOrder regenerates the playback order if the tracks were changed bypassing the Item methods.
+func (pl *Item) checkOrder() {
+	if len(pl.order) == len(pl.Tracks) {
+		return
+	}
+	pl.Shuffle(pl.smart)
+}
+
+// orderInsert places the new track index at a random position among the tracks that are not played yet.
+func (pl *Item) orderInsert(index int) {
+	pos := pl.orderPos + 1
+	if pos < len(pl.order) {
+		pos += rand.Intn(len(pl.order) - pos + 1)
+	}
+	pl.order = slices.Insert(pl.order, min(pos, len(pl.order)), index)
+}
+
+func (pl *Item) orderRemove(index int) {
+	pos := slices.Index(pl.order, index)
+	if pos >= 0 {
+		pl.order = slices.Delete(pl.order, pos, pos+1)
+		if pos <= pl.orderPos {
+			pl.orderPos--
+		}
+	}
+	for i := range pl.order {
+		if pl.order[i] > index {
+			pl.order[i]--
+		}
+	}
+}
+
+// spreadArtists reorders the shuffled tracks so that the neighbours have different artists where possible.
+func (pl *Item) spreadArtists() {
+	for i := 1; i < len(pl.order); i++ {
+		prev := &pl.Tracks[pl.order[i-1]]
+		if !sameArtist(prev, &pl.Tracks[pl.order[i]]) {
+			continue
+		}
+		for j := i + 1; j < len(pl.order); j++ {
+			if !sameArtist(prev, &pl.Tracks[pl.order[j]]) {
+				pl.order[i], pl.order[j] = pl.order[j], pl.order[i]
+				break
+			}
+		}
+	}
+}
+
+func sameArtist(a, b *api.Track) bool {
+	for i := range a.Artists {
+		for j := range b.Artists {
+			if a.Artists[i].Id != 0 && a.Artists[i].Id == b.Artists[j].Id {
+				return true
+			}
+			if a.Artists[i].Id == 0 && a.Artists[i].Name == b.Artists[j].Name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -48,6 +48,10 @@ func (d ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	}
 
 	name := item.Name
+	if item.Offline {
+		name = style.IconCached + " " + name
+	}
+
 	nameLen := lipgloss.Width(name)
 	maxLen := m.Width() - 5
 	if nameLen > maxLen {
@@ -18,6 +18,12 @@ const (
 	CURSOR_UP Control = iota
 	CURSOR_DOWN
 	RENAME
+	EDIT
+	OPEN_USER
+	PIN
+	OFFLINE
+	EXPORT
+	IMPORT
 	TOGGLE_VIEW
 )
 
@@ -26,8 +32,14 @@ type PlaylistType = uint64
 const (
 	NONE PlaylistType = iota
 	MYWAVE
+	WAVE
 	LIKES
+	DISLIKES
 	LOCAL
+	QUEUE
+	HISTORY
+	STATS
+	DOWNLOADS
 	// Should be the last to detect downloaded user playlists
 	USER
 )
@@ -35,7 +47,12 @@ const (
 var defaultPlaylists = []list.Item{
 	&Item{Name: "my wave", Kind: MYWAVE, Active: true, Subitem: false, Rotor: true},
 	&Item{Name: "likes", Kind: LIKES, Active: true, Subitem: false},
+	&Item{Name: "dislikes", Kind: DISLIKES, Active: true, Subitem: false},
 	&Item{Name: "local", Kind: LOCAL, Active: true, Subitem: false},
+	&Item{Name: "queue", Kind: QUEUE, Active: true, Subitem: false},
+	&Item{Name: "history", Kind: HISTORY, Active: true, Subitem: false},
+	&Item{Name: "stats", Kind: STATS, Active: true, Subitem: false},
+	&Item{Name: "downloads", Kind: DOWNLOADS, Active: true, Subitem: false},
 
 	&Item{Name: "", Kind: NONE, Active: false, Subitem: false},
 	&Item{Name: "playlists:", Kind: NONE, Active: false, Subitem: false},
@@ -81,6 +98,9 @@ func (m *Model) View() string {
 	}
 
 	m.helpMap.Renamable = m.SelectedItem().Kind >= USER
+	m.helpMap.Pinnable = m.SelectedItem().IsRemote()
+	m.helpMap.Offlinable = m.SelectedItem().Kind == LIKES || m.SelectedItem().Kind >= USER || m.SelectedItem().IsRemote()
+	m.helpMap.Exportable = len(m.SelectedItem().Tracks) > 0
 	if m.help.ShowAll {
 		m.list.SetHeight(m.height - 3)
 	} else {
@@ -125,6 +145,18 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 			cmds = append(cmds, model.Cmd(CURSOR_DOWN))
 		case controls.PlaylistsRename.Contains(keypress):
 			cmds = append(cmds, model.Cmd(RENAME))
+		case controls.PlaylistsEdit.Contains(keypress):
+			cmds = append(cmds, model.Cmd(EDIT))
+		case controls.PlaylistsOpenUser.Contains(keypress):
+			cmds = append(cmds, model.Cmd(OPEN_USER))
+		case controls.PlaylistsPin.Contains(keypress):
+			cmds = append(cmds, model.Cmd(PIN))
+		case controls.PlaylistsOffline.Contains(keypress):
+			cmds = append(cmds, model.Cmd(OFFLINE))
+		case controls.PlaylistsExport.Contains(keypress):
+			cmds = append(cmds, model.Cmd(EXPORT))
+		case controls.PlaylistsImport.Contains(keypress):
+			cmds = append(cmds, model.Cmd(IMPORT))
 		case controls.PlaylistsHide.Contains(keypress):
 			m.Hidden = !m.Hidden
 			cmds = append(cmds, model.Cmd(TOGGLE_VIEW))
@@ -146,6 +178,16 @@ func (m *Model) GetFirst(kind PlaylistType) (*Item, int) {
 	return nil, -1
 }
 
+func (m *Model) IndexOf(item *Item) int {
+	items := m.list.Items()
+	for i := range items {
+		if items[i].(*Item) == item {
+			return i
+		}
+	}
+	return -1
+}
+
 func (m *Model) Items() []*Item {
 	litems := m.list.Items()
 	items := make([]*Item, len(litems))
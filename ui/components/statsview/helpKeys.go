@@ -0,0 +1,36 @@
+package statsview
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/dece2183/yamusic-tui/config"
+)
+
+type helpKeyMap struct {
+	Period        key.Binding
+	Sort          key.Binding
+	ShowHelp      key.Binding
+	CloseHelp     key.Binding
+	HideTracklist key.Binding
+}
+
+func newHelpMap() *helpKeyMap {
+	controls := config.Current.Controls
+	return &helpKeyMap{
+		Period:        key.NewBinding(controls.StatsPeriod.Binding(), controls.StatsPeriod.Help("period")),
+		Sort:          key.NewBinding(controls.StatsSort.Binding(), controls.StatsSort.Help("sort by plays/time")),
+		HideTracklist: key.NewBinding(controls.TracksHide.Binding(), controls.TracksHide.Help("hide")),
+		ShowHelp:      key.NewBinding(controls.ShowAllKeys.Binding(), controls.ShowAllKeys.Help("show keys")),
+		CloseHelp:     key.NewBinding(controls.ShowAllKeys.Binding(), controls.ShowAllKeys.Help("hide keys")),
+	}
+}
+
+func (k helpKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Period, k.Sort, k.ShowHelp}
+}
+
+func (k helpKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Period, k.Sort},
+		{k.HideTracklist, k.CloseHelp},
+	}
+}
@@ -0,0 +1,165 @@
+package statsview
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/stats"
+	"github.com/dece2183/yamusic-tui/ui/style"
+)
+
+type Model struct {
+	help          help.Model
+	helpMap       *helpKeyMap
+	width, height int
+	report        *stats.Report
+	period        int
+	byTime        bool
+}
+
+func New() *Model {
+	m := &Model{
+		help:    help.New(),
+		helpMap: newHelpMap(),
+		period:  slices.Index(stats.PeriodNames, "year"),
+	}
+
+	m.help.Ellipsis = "…"
+	m.help.Styles.FullDesc = m.help.Styles.FullDesc.PaddingRight(1)
+
+	return m
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) View() string {
+	helpView := m.help.View(m.helpMap)
+	contentWidth := m.width - 6
+	contentHeight := m.height - lipgloss.Height(helpView) - 5
+
+	var content string
+	if m.report == nil {
+		content = style.ErrorTextStyle.Render("unable to read the playback history")
+	} else {
+		content = m.renderReport(contentWidth, contentHeight)
+	}
+
+	content = lipgloss.NewStyle().Width(contentWidth).Height(contentHeight).MaxHeight(contentHeight).Render(content)
+	return style.TrackBoxStyle.Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, content, "", helpView))
+}
+
+func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
+	switch msg := message.(type) {
+	case tea.KeyMsg:
+		controls := config.Current.Controls
+		keypress := msg.String()
+
+		switch {
+		case controls.StatsPeriod.Contains(keypress):
+			m.period = (m.period + 1) % len(stats.PeriodNames)
+			m.Refresh()
+		case controls.StatsSort.Contains(keypress):
+			m.byTime = !m.byTime
+			if m.report != nil {
+				m.report.Sort(m.byTime)
+			}
+		case controls.ShowAllKeys.Contains(keypress):
+			m.help.ShowAll = !m.help.ShowAll
+		}
+	}
+
+	return m, nil
+}
+
+// Refresh recomputes the statistics from the playback history.
+func (m *Model) Refresh() {
+	period, _ := stats.ParsePeriod(stats.PeriodNames[m.period], time.Now())
+	report, err := stats.Compute(period)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to compute listening statistics: %s", err)
+		m.report = nil
+		return
+	}
+
+	report.Sort(m.byTime)
+	m.report = report
+}
+
+func (m *Model) SetWidth(width int) {
+	m.width = width
+	m.help.Width = width - 6
+}
+
+func (m *Model) SetHeight(height int) {
+	m.height = height
+}
+
+func (m *Model) renderReport(width, height int) string {
+	r := m.report
+
+	sortName := "plays"
+	if m.byTime {
+		sortName = "listening time"
+	}
+
+	title := style.TrackListTitleStyle.Render(fmt.Sprintf("Listening statistics for %s", periodTitle(r.Period)))
+	summary := style.TrackArtistStyle.Render(fmt.Sprintf("%d plays, %s, sorted by %s", r.Plays, stats.FormatDuration(r.Time), sortName))
+
+	categories := []struct {
+		title string
+		items []stats.Item
+	}{
+		{"Top tracks", r.Tracks},
+		{"Top artists", r.Artists},
+		{"Top albums", r.Albums},
+		{"Top genres", r.Genres},
+	}
+
+	// each category takes the title line, the items and the separating line
+	rows := max((height-3)/len(categories)-2, 1)
+
+	lines := []string{title, summary}
+	for _, c := range categories {
+		lines = append(lines, "", style.TrackTitleStyle.Render(c.title))
+		if len(c.items) == 0 {
+			lines = append(lines, style.TrackVersionStyle.Render("  no data"))
+			continue
+		}
+		for i, item := range c.items[:min(rows, len(c.items))] {
+			info := fmt.Sprintf(" %d plays, %s", item.Plays, stats.FormatDuration(item.Time))
+			name := fmt.Sprintf("%2d. %s", i+1, item.Name)
+			nameWidth := max(width-lipgloss.Width(info), 1)
+			if lipgloss.Width(name) > nameWidth {
+				name = lipgloss.NewStyle().MaxWidth(nameWidth-1).Render(name) + "…"
+			}
+			gap := strings.Repeat(" ", max(width-lipgloss.Width(name)-lipgloss.Width(info), 0))
+			lines = append(lines, name+gap+style.TrackArtistStyle.Render(info))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func periodTitle(p stats.Period) string {
+	switch p.Name {
+	case "week":
+		return "the last week"
+	case "month":
+		return "the last month"
+	case "year":
+		return fmt.Sprint(p.From.Year())
+	case "all":
+		return "all time"
+	default:
+		return p.Name
+	}
+}
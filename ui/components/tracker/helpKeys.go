@@ -12,12 +12,14 @@ type helpKeyMap struct {
 	PrevTrack    key.Binding
 	NextTrack    key.Binding
 	LikeUnlike   key.Binding
+	Dislike      key.Binding
 	CacheTrack   key.Binding
 	Forward      key.Binding
 	Backward     key.Binding
 	VolUp        key.Binding
 	VolDown      key.Binding
 	ToggleLyrics key.Binding
+	Repeat       key.Binding
 	HidePlayer   key.Binding
 }
 
@@ -40,6 +42,10 @@ func newHelpMap() *helpKeyMap {
 			controls.PlayerLike.Binding(),
 			controls.PlayerLike.Help("like/unlike"),
 		),
+		Dislike: key.NewBinding(
+			controls.PlayerDislike.Binding(),
+			controls.PlayerDislike.Help("dislike"),
+		),
 		CacheTrack: key.NewBinding(
 			controls.PlayerCache.Binding(),
 			controls.PlayerCache.Help("cache track"),
@@ -64,6 +70,10 @@ func newHelpMap() *helpKeyMap {
 			controls.PlayerToggleLyrics.Binding(),
 			controls.PlayerToggleLyrics.Help("lyrics"),
 		),
+		Repeat: key.NewBinding(
+			controls.PlayerRepeat.Binding(),
+			controls.PlayerRepeat.Help("repeat"),
+		),
 		HidePlayer: key.NewBinding(
 			controls.PlayerHide.Binding(),
 			controls.PlayerHide.Help("hide"),
@@ -77,8 +87,8 @@ func (k helpKeyMap) ShortHelp() []key.Binding {
 
 func (k helpKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.PlayPause, k.LikeUnlike, k.ToggleLyrics, k.CacheTrack},
-		{k.NextTrack, k.PrevTrack, k.Forward, k.Backward},
+		{k.PlayPause, k.LikeUnlike, k.Dislike, k.ToggleLyrics, k.CacheTrack},
+		{k.NextTrack, k.PrevTrack, k.Forward, k.Backward, k.Repeat},
 		{k.VolUp, k.VolDown, k.HidePlayer},
 	}
 }
@@ -37,6 +37,9 @@ const (
 	BUFFERING_COMPLETE
 	TOGGLE_LYRICS
 	TOGGLE_VIEW
+	REPEAT
+	TRACK_ENDED
+	DISLIKE
 )
 
 type ProgressControl float64
@@ -62,9 +65,17 @@ type Model struct {
 	helpMap    *helpKeyMap
 	Hidden     bool
 	showLyrics bool
+	repeat     config.RepeatMode
+	shuffle    bool
 	showError  bool
 	errorText  string
 
+	showTask  bool
+	taskText  string
+	taskDone  int
+	taskTotal int
+	taskBar   progress.Model
+
 	paused         bool
 	playtime       time.Duration
 	playStarted    time.Time
@@ -85,11 +96,13 @@ func New(p *tea.Program, likesMap *map[string]bool) *Model {
 		likesMap:   likesMap,
 		progress:   progress.New(),
 		volumeBar:  progress.New(),
+		taskBar:    progress.New(),
 		help:       help.New(),
 		helpMap:    newHelpMap(),
 		paused:     true,
 		volume:     config.Current.Volume,
 		showLyrics: config.Current.ShowLyrics,
+		repeat:     config.Current.Repeat,
 	}
 
 	m.volumeIncremet = m.volume / _VOLUME_FADE_STEPS
@@ -104,6 +117,10 @@ func New(p *tea.Program, likesMap *map[string]bool) *Model {
 	m.volumeBar.EmptyColor = string(style.BackgroundColor)
 	m.volumeBar.Width = style.VolumeIndicatorWidth
 
+	m.taskBar.ShowPercentage = false
+	m.taskBar.FullColor = string(style.AccentColor)
+	m.taskBar.EmptyColor = string(style.BackgroundColor)
+
 	m.help.Ellipsis = "…"
 	m.trackWrapper = &readWrapper{program: m.program}
 
@@ -167,6 +184,13 @@ func (m *Model) View() string {
 		tracker = lipgloss.JoinVertical(lipgloss.Left, m.renderLyrics(), "", tracker)
 	}
 
+	if m.showTask {
+		taskText := fmt.Sprintf("%s %d/%d ", m.taskText, m.taskDone, m.taskTotal)
+		m.taskBar.Width = max(m.width-lipgloss.Width(taskText)-4, 0)
+		taskText += m.taskBar.ViewAs(float64(m.taskDone) / float64(max(m.taskTotal, 1)))
+		tracker = lipgloss.JoinVertical(lipgloss.Left, taskText, "", tracker)
+	}
+
 	if m.showError && !config.Current.SuppressErrors {
 		errText := "Error: " + m.errorText + "; -> " + log.Location()
 		maxLen := m.Width() - 4
@@ -196,6 +220,17 @@ func (m *Model) View() string {
 			int(durTotal.Seconds())%60,
 		))
 
+		var playbackMode string
+		switch m.repeat {
+		case config.REPEAT_ONE:
+			playbackMode = style.IconRepeatOne + " "
+		case config.REPEAT_ALL:
+			playbackMode = style.IconRepeatAll + " "
+		}
+		if m.shuffle {
+			playbackMode += style.IconShuffle + " "
+		}
+
 		var trackLike string
 		if (*m.likesMap)[m.track.Id] {
 			trackLike = style.IconLiked + " "
@@ -203,7 +238,7 @@ func (m *Model) View() string {
 			trackLike = style.IconNotLiked + " "
 		}
 
-		trackAddInfo := style.TrackAddInfoStyle.Render(trackLike + trackTime)
+		trackAddInfo := style.TrackAddInfoStyle.Render(playbackMode + trackLike + trackTime)
 		addInfoLen := lipgloss.Width(trackAddInfo)
 		maxLen := m.Width() - addInfoLen - 4
 		stl := lipgloss.NewStyle().MaxWidth(maxLen - 1)
@@ -278,6 +313,9 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 		case controls.PlayerLike.Contains(keypress):
 			cmds = append(cmds, model.Cmd(LIKE))
 
+		case controls.PlayerDislike.Contains(keypress):
+			cmds = append(cmds, model.Cmd(DISLIKE))
+
 		case controls.PlayerCache.Contains(keypress):
 			if !m.IsStoped() {
 				m.trackWrapper.trackBuffer.BufferAll()
@@ -295,6 +333,9 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 		case controls.PlayerToggleLyrics.Contains(keypress):
 			m.SetLirycs(!m.showLyrics)
 			cmds = append(cmds, model.Cmd(TOGGLE_LYRICS))
+		case controls.PlayerRepeat.Contains(keypress):
+			m.SetRepeat((m.repeat + 1) % (config.REPEAT_ALL + 1))
+			cmds = append(cmds, model.Cmd(REPEAT))
 		case controls.PlayerHide.Contains(keypress):
 			m.Hidden = !m.Hidden
 			cmds = append(cmds, model.Cmd(TOGGLE_VIEW))
@@ -344,6 +385,9 @@ func (m *Model) Height() int {
 	if m.showError && !config.Current.SuppressErrors {
 		baseHeight += 2
 	}
+	if m.showTask {
+		baseHeight += 2
+	}
 	return baseHeight
 }
 
@@ -373,6 +417,21 @@ func (m *Model) SetLirycs(show bool) {
 	config.Save()
 }
 
+func (m *Model) SetRepeat(mode config.RepeatMode) {
+	m.repeat = mode
+	config.Current.Repeat = m.repeat
+	config.Save()
+}
+
+func (m *Model) Repeat() config.RepeatMode {
+	return m.repeat
+}
+
+// SetShuffle only changes the shuffle indicator; the play order is controlled by the playlist.
+func (m *Model) SetShuffle(shuffle bool) {
+	m.shuffle = shuffle
+}
+
 func (m *Model) Volume() float64 {
 	return m.volume
 }
@@ -414,7 +473,9 @@ func (m *Model) Stop() {
 	m.trackWrapper.Close()
 	m.player.Close()
 	m.player = nil
-	m.playtime += time.Since(m.playStarted)
+	if !m.paused {
+		m.playtime += time.Since(m.playStarted)
+	}
 	m.paused = true
 }
 
@@ -517,6 +578,18 @@ func (m *Model) HideError() {
 	m.showError = false
 }
 
+// ShowProgress displays the progress of the background task above the player.
+func (m *Model) ShowProgress(text string, done, total int) {
+	m.showTask = true
+	m.taskText = text
+	m.taskDone = done
+	m.taskTotal = total
+}
+
+func (m *Model) HideProgress() {
+	m.showTask = false
+}
+
 func (m *Model) dynamicVolumeStep() float64 {
 	now := time.Now()
 	delta := now.Sub(m.lastVolumeKey)
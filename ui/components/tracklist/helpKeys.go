@@ -12,17 +12,31 @@ type helpKeyMap struct {
 	PageDown           key.Binding
 	Play               key.Binding
 	LikeUnlike         key.Binding
+	Dislike            key.Binding
+	DislikeArtist      key.Binding
+	Wave               key.Binding
+	Mark               key.Binding
+	MarkRange          key.Binding
+	MarkAll            key.Binding
+	Cache              key.Binding
+	Export             key.Binding
 	AddToPlaylist      key.Binding
 	RemoveFromPlaylist key.Binding
 	Search             key.Binding
 	Share              key.Binding
 	Shuffle            key.Binding
+	AddToQueue         key.Binding
+	PlayNext           key.Binding
+	QueueAll           key.Binding
+	MoveUp             key.Binding
+	MoveDown           key.Binding
 	Reload             key.Binding
 	ShowHelp           key.Binding
 	CloseHelp          key.Binding
 	HideTracklist      key.Binding
 
-	Shafflable bool
+	Shafflable  bool
+	Reorderable bool
 }
 
 func newHelpMap() *helpKeyMap {
@@ -34,11 +48,24 @@ func newHelpMap() *helpKeyMap {
 		PageDown:           key.NewBinding(controls.TracksPrevPage.Binding(), controls.TracksPrevPage.Help("page down")),
 		Play:               key.NewBinding(controls.Apply.Binding(), controls.Apply.Help("play")),
 		LikeUnlike:         key.NewBinding(controls.TracksLike.Binding(), controls.TracksLike.Help("like/unlike")),
+		Dislike:            key.NewBinding(controls.TracksDislike.Binding(), controls.TracksDislike.Help("dislike")),
+		DislikeArtist:      key.NewBinding(controls.TracksDislikeArtist.Binding(), controls.TracksDislikeArtist.Help("dislike artist")),
+		Mark:               key.NewBinding(controls.TracksMark.Binding(), controls.TracksMark.Help("mark")),
+		MarkRange:          key.NewBinding(controls.TracksMarkRange.Binding(), controls.TracksMarkRange.Help("mark range")),
+		MarkAll:            key.NewBinding(controls.TracksMarkAll.Binding(), controls.TracksMarkAll.Help("mark all")),
+		Cache:              key.NewBinding(controls.TracksCache.Binding(), controls.TracksCache.Help("cache")),
+		Export:             key.NewBinding(controls.TracksExport.Binding(), controls.TracksExport.Help("export")),
+		Wave:               key.NewBinding(controls.TracksWave.Binding(), controls.TracksWave.Help("wave from")),
 		AddToPlaylist:      key.NewBinding(controls.TracksAddToPlaylist.Binding(), controls.TracksAddToPlaylist.Help("add to")),
 		RemoveFromPlaylist: key.NewBinding(controls.TracksRemoveFromPlaylist.Binding(), controls.TracksRemoveFromPlaylist.Help("remove")),
 		Search:             key.NewBinding(controls.TracksSearch.Binding(), controls.TracksSearch.Help("search")),
 		Share:              key.NewBinding(controls.TracksShare.Binding(), controls.TracksShare.Help("share")),
 		Shuffle:            key.NewBinding(controls.TracksShuffle.Binding(), controls.TracksShuffle.Help("shuffle")),
+		AddToQueue:         key.NewBinding(controls.TracksAddToQueue.Binding(), controls.TracksAddToQueue.Help("queue")),
+		PlayNext:           key.NewBinding(controls.TracksPlayNext.Binding(), controls.TracksPlayNext.Help("play next")),
+		QueueAll:           key.NewBinding(controls.TracksQueueAll.Binding(), controls.TracksQueueAll.Help("queue all")),
+		MoveUp:             key.NewBinding(controls.TracksMoveUp.Binding(), controls.TracksMoveUp.Help("move up")),
+		MoveDown:           key.NewBinding(controls.TracksMoveDown.Binding(), controls.TracksMoveDown.Help("move down")),
 		Reload:             key.NewBinding(controls.Reload.Binding(), controls.Reload.Help("reload")),
 		HideTracklist:      key.NewBinding(controls.TracksHide.Binding(), controls.TracksHide.Help("hide")),
 		ShowHelp:           key.NewBinding(controls.ShowAllKeys.Binding(), controls.ShowAllKeys.Help("show keys")),
@@ -53,12 +80,18 @@ func (k helpKeyMap) ShortHelp() []key.Binding {
 func (k helpKeyMap) FullHelp() [][]key.Binding {
 	bindings := [][]key.Binding{
 		{k.CursorUp, k.CursorDown, k.PageUp, k.PageDown},
-		{k.Play, k.LikeUnlike, k.AddToPlaylist, k.RemoveFromPlaylist},
-		{k.Search, k.Share},
+		{k.Mark, k.MarkRange, k.MarkAll},
+		{k.Play, k.LikeUnlike, k.AddToPlaylist, k.RemoveFromPlaylist, k.Cache, k.Export},
+		{k.AddToQueue, k.PlayNext, k.QueueAll, k.Wave},
+		{k.Dislike, k.DislikeArtist, k.Search, k.Share},
 	}
 
 	if k.Shafflable {
-		bindings[2] = append(bindings[2], k.Shuffle)
+		bindings[4] = append(bindings[4], k.Shuffle)
+	}
+
+	if k.Reorderable {
+		bindings = append(bindings, []key.Binding{k.MoveUp, k.MoveDown})
 	}
 
 	return append(bindings, []key.Binding{k.Reload, k.HideTracklist, k.CloseHelp})
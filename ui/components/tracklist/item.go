@@ -10,6 +10,7 @@ type Item struct {
 	Artists      string
 	IsPlaying    bool
 	IsSuggestion bool
+	IsMarked     bool
 }
 
 func NewItem(track *api.Track) Item {
@@ -9,12 +9,15 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/ui/style"
 )
 
 type ItemDelegate struct {
 	likesMap *map[string]bool
 	cacheMap *map[string]bool
+	// reports whether the track or its artist is disliked
+	isDisliked func(track *api.Track) bool
 }
 
 func (d ItemDelegate) Height() int {
@@ -53,6 +56,9 @@ func (d ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	if item.IsPlaying {
 		trackTitle = style.AccentTextStyle.Render(style.IconPlay) + " "
 	}
+	if item.IsMarked {
+		trackTitle += style.IconMarked + " "
+	}
 	if item.Track.Available {
 		trackTitle += trackTitleStyle.Render(item.Track.Title)
 	} else {
@@ -72,6 +78,8 @@ func (d ItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	var trackLike string
 	if (*d.likesMap)[item.Track.Id] {
 		trackLike = style.IconLiked
+	} else if d.isDisliked != nil && d.isDisliked(item.Track) {
+		trackLike = style.IconDisliked
 	} else {
 		trackLike = style.IconNotLiked
 	}
@@ -1,8 +1,11 @@
 package tracklist
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/config"
 	"github.com/dece2183/yamusic-tui/ui/model"
 	"github.com/dece2183/yamusic-tui/ui/style"
@@ -26,8 +29,18 @@ const (
 	SHUFFLE
 	SHARE
 	LIKE
+	DISLIKE
+	DISLIKE_ARTIST
+	WAVE
 	ADD_TO_PLAYLIST
 	REMOVE_FROM_PLAYLIST
+	ADD_TO_QUEUE
+	PLAY_NEXT
+	QUEUE_ALL
+	MOVE_UP
+	MOVE_DOWN
+	CACHE
+	EXPORT
 	TOGGLE_VIEW
 )
 
@@ -40,19 +53,25 @@ type Model struct {
 	Hidden        bool
 	Title         string
 	Shufflable    bool
+	Reorderable   bool
+
+	rangeAnchor int
+	rangeBase   []int
 }
 
-func New(p *tea.Program, likesMap *map[string]bool, cacheMap *map[string]bool) *Model {
+func New(p *tea.Program, likesMap *map[string]bool, cacheMap *map[string]bool, isDisliked func(track *api.Track) bool) *Model {
 	m := &Model{
 		program: p,
 		help:    help.New(),
 		helpMap: newHelpMap(),
 		Title:   "Tracks",
+
+		rangeAnchor: -1,
 	}
 
 	controls := config.Current.Controls
 
-	m.list = list.New([]list.Item{}, ItemDelegate{likesMap: likesMap, cacheMap: cacheMap}, 512, 512)
+	m.list = list.New([]list.Item{}, ItemDelegate{likesMap: likesMap, cacheMap: cacheMap, isDisliked: isDisliked}, 512, 512)
 	m.list.Styles.Title = style.TrackListTitleStyle
 	m.list.KeyMap = list.KeyMap{
 		CursorUp:   key.NewBinding(controls.CursorUp.Binding(), controls.CursorUp.Help("up")),
@@ -79,14 +98,24 @@ func (m *Model) View() string {
 		return ""
 	}
 
+	var markedInfo string
+	if marked := len(m.MarkedIndexes()); marked > 0 {
+		markedInfo = fmt.Sprintf(" %s %d", style.IconMarked, marked)
+		if m.rangeAnchor >= 0 {
+			markedInfo += "…"
+		}
+	}
+
+	markedInfoLen := lipgloss.Width(markedInfo)
 	titleLen := lipgloss.Width(m.Title)
-	if titleLen > m.width-8 {
-		m.list.Title = lipgloss.NewStyle().MaxWidth(m.width-9).Render(m.Title) + "…"
+	if titleLen > m.width-8-markedInfoLen {
+		m.list.Title = lipgloss.NewStyle().MaxWidth(m.width-9-markedInfoLen).Render(m.Title) + "…" + markedInfo
 	} else {
-		m.list.Title = m.Title
+		m.list.Title = m.Title + markedInfo
 	}
 
 	m.helpMap.Shafflable = m.Shufflable
+	m.helpMap.Reorderable = m.Reorderable
 	helpView := m.help.View(m.helpMap)
 	m.list.SetHeight(m.height - lipgloss.Height(helpView) - 4)
 
@@ -119,12 +148,16 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 		case controls.Apply.Contains(keypress):
 			cmds = append(cmds, model.Cmd(PLAY))
 		case controls.CursorUp.Contains(keypress):
+			m.markRange()
 			cmds = append(cmds, model.Cmd(CURSOR_UP))
 		case controls.CursorDown.Contains(keypress):
+			m.markRange()
 			cmds = append(cmds, model.Cmd(CURSOR_DOWN))
 		case controls.TracksNextPage.Contains(keypress):
+			m.markRange()
 			cmds = append(cmds, model.Cmd(PAGE_UP))
 		case controls.TracksPrevPage.Contains(keypress):
+			m.markRange()
 			cmds = append(cmds, model.Cmd(PAGE_DOWN))
 		case controls.TracksSearch.Contains(keypress):
 			cmds = append(cmds, model.Cmd(SEARCH))
@@ -134,10 +167,38 @@ func (m *Model) Update(message tea.Msg) (*Model, tea.Cmd) {
 			cmds = append(cmds, model.Cmd(SHARE))
 		case controls.TracksLike.Contains(keypress):
 			cmds = append(cmds, model.Cmd(LIKE))
+		case controls.TracksDislike.Contains(keypress):
+			cmds = append(cmds, model.Cmd(DISLIKE))
+		case controls.TracksDislikeArtist.Contains(keypress):
+			cmds = append(cmds, model.Cmd(DISLIKE_ARTIST))
+		case controls.TracksWave.Contains(keypress):
+			cmds = append(cmds, model.Cmd(WAVE))
 		case controls.TracksAddToPlaylist.Contains(keypress):
 			cmds = append(cmds, model.Cmd(ADD_TO_PLAYLIST))
 		case controls.TracksRemoveFromPlaylist.Contains(keypress):
 			cmds = append(cmds, model.Cmd(REMOVE_FROM_PLAYLIST))
+		case controls.TracksAddToQueue.Contains(keypress):
+			cmds = append(cmds, model.Cmd(ADD_TO_QUEUE))
+		case controls.TracksPlayNext.Contains(keypress):
+			cmds = append(cmds, model.Cmd(PLAY_NEXT))
+		case controls.TracksQueueAll.Contains(keypress):
+			cmds = append(cmds, model.Cmd(QUEUE_ALL))
+		case controls.TracksMoveUp.Contains(keypress):
+			cmds = append(cmds, model.Cmd(MOVE_UP))
+		case controls.TracksMoveDown.Contains(keypress):
+			cmds = append(cmds, model.Cmd(MOVE_DOWN))
+		case controls.TracksCache.Contains(keypress):
+			cmds = append(cmds, model.Cmd(CACHE))
+		case controls.TracksExport.Contains(keypress):
+			cmds = append(cmds, model.Cmd(EXPORT))
+		case controls.TracksMark.Contains(keypress):
+			m.toggleMark()
+		case controls.TracksMarkRange.Contains(keypress):
+			m.toggleMarkRange()
+		case controls.TracksMarkAll.Contains(keypress):
+			m.toggleMarkAll()
+		case controls.Cancel.Contains(keypress):
+			m.ClearMarks()
 		case controls.TracksHide.Contains(keypress):
 			m.Hidden = !m.Hidden
 			cmds = append(cmds, model.Cmd(TOGGLE_VIEW))
@@ -157,6 +218,7 @@ func (m *Model) Items() []Item {
 }
 
 func (m *Model) SetItems(items []Item) tea.Cmd {
+	m.rangeAnchor = -1
 	newItems := make([]list.Item, len(items))
 	for i := 0; i < len(items); i++ {
 		newItems[i] = items[i]
@@ -184,6 +246,105 @@ func (m *Model) SetItem(index int, item Item) tea.Cmd {
 	return m.list.SetItem(index, item)
 }
 
+func (m *Model) toggleMark() {
+	if len(m.list.Items()) == 0 {
+		return
+	}
+
+	m.rangeAnchor = -1
+
+	index := m.list.Index()
+	item := m.list.SelectedItem().(Item)
+	item.IsMarked = !item.IsMarked
+	m.list.SetItem(index, item)
+	m.list.CursorDown()
+}
+
+// toggleMarkRange starts marking the tracks between the cursor position and the position where it was started,
+// the range is fixed when toggled again.
+func (m *Model) toggleMarkRange() {
+	if m.rangeAnchor >= 0 || len(m.list.Items()) == 0 {
+		m.rangeAnchor = -1
+		return
+	}
+
+	m.rangeAnchor = m.list.Index()
+	m.rangeBase = m.MarkedIndexes()
+	m.markRange()
+}
+
+func (m *Model) markRange() {
+	if m.rangeAnchor < 0 {
+		return
+	}
+
+	from, to := m.rangeAnchor, m.list.Index()
+	if from > to {
+		from, to = to, from
+	}
+
+	for i, listItem := range m.list.Items() {
+		item := listItem.(Item)
+		_, marked := slices.BinarySearch(m.rangeBase, i)
+		marked = marked || (i >= from && i <= to)
+		if item.IsMarked != marked {
+			item.IsMarked = marked
+			m.list.SetItem(i, item)
+		}
+	}
+}
+
+// toggleMarkAll marks all tracks or clears the marks if all tracks are already marked.
+func (m *Model) toggleMarkAll() {
+	items := m.list.Items()
+	if len(m.MarkedIndexes()) == len(items) {
+		m.ClearMarks()
+		return
+	}
+
+	m.rangeAnchor = -1
+	for i, listItem := range items {
+		item := listItem.(Item)
+		if !item.IsMarked {
+			item.IsMarked = true
+			m.list.SetItem(i, item)
+		}
+	}
+}
+
+// MarkedIndexes returns the indexes of the marked tracks in ascending order.
+func (m *Model) MarkedIndexes() []int {
+	var indexes []int
+	for i, item := range m.list.Items() {
+		if item.(Item).IsMarked {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (m *Model) SetMarked(indexes []int) {
+	items := m.list.Items()
+	for _, i := range indexes {
+		if i >= 0 && i < len(items) {
+			item := items[i].(Item)
+			item.IsMarked = true
+			m.list.SetItem(i, item)
+		}
+	}
+}
+
+func (m *Model) ClearMarks() {
+	m.rangeAnchor = -1
+	for i, listItem := range m.list.Items() {
+		item := listItem.(Item)
+		if item.IsMarked {
+			item.IsMarked = false
+			m.list.SetItem(i, item)
+		}
+	}
+}
+
 func (m *Model) SelectedItem() Item {
 	return m.list.SelectedItem().(Item)
 }
@@ -1,19 +1,24 @@
 package mainpage
 
 import (
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/bogem/id3v2/v2"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/cache"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/library"
 	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/stream"
 	"github.com/dece2183/yamusic-tui/ui/components/playlist"
 )
 
 func (m *Model) cacheCurrentTrack() tea.Cmd {
 	currentTrack := m.tracker.CurrentTrack()
-	if m.tracker.IsStoped() || m.cachedTracksMap[currentTrack.Id] {
+	if m.tracker.IsStoped() || m.cachedTracksMap[currentTrack.Id] || library.IsLocal(currentTrack) {
 		return nil
 	}
 
@@ -26,7 +31,7 @@ func (m *Model) cacheCurrentTrack() tea.Cmd {
 
 	defer metadataFile.Close()
 
-	cacheFile, err := cache.Write(currentTrack.Id)
+	cacheFile, err := cache.Write(currentTrack)
 	if err != nil {
 		log.Print(log.LVL_ERROR, "failed to write cache file: %s", err)
 		m.tracker.ShowError("cache write")
@@ -37,8 +42,21 @@ func (m *Model) cacheCurrentTrack() tea.Cmd {
 
 	tag := id3v2.NewEmptyTag()
 	tag.Reset(metadataFile, id3v2.Options{Parse: true})
-	tag.WriteTo(cacheFile)
-	m.tracker.TrackBuffer().WriteTo(cacheFile)
+	_, err = tag.WriteTo(cacheFile)
+	if err == nil {
+		err = writeTrackBuffer(cacheFile, m.tracker.TrackBuffer())
+	}
+	if err == nil {
+		err = cacheFile.Commit()
+	}
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to write cache file: %s", err)
+		m.tracker.ShowError("cache write")
+		return nil
+	}
+
+	cover, _ := os.ReadFile(m.coverFilePath(currentTrack))
+	cacheExtras(currentTrack, cover, m.playingLyrics)
 
 	m.cachedTracksMap[currentTrack.Id] = true
 	cachePlaylist, index := m.playlists.GetFirst(playlist.LOCAL)
@@ -49,11 +67,127 @@ func (m *Model) cacheCurrentTrack() tea.Cmd {
 		m.displayPlaylist(cachePlaylist)
 	}
 
+	m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	return tea.Batch(cmd, m.evictCache())
+}
+
+// cacheExtras stores the track cover and lyrics next to the cached track.
+func cacheExtras(track *api.Track, cover []byte, lrc string) {
+	if len(cover) > 0 {
+		err := cache.WriteCover(track.Id, cover)
+		if err != nil {
+			log.Print(log.LVL_WARNIGN, "failed to cache track [%s] cover: %s", track.Id, err)
+		}
+	}
+	if len(lrc) > 0 {
+		err := cache.WriteLyrics(track.Id, lrc)
+		if err != nil {
+			log.Print(log.LVL_WARNIGN, "failed to cache track [%s] lyrics: %s", track.Id, err)
+		}
+	}
+}
+
+// writeTrackBuffer writes the buffered track, the track that is not completely buffered is not written.
+func writeTrackBuffer(w io.Writer, buffer *stream.BufferedStream) error {
+	if buffer.BufferingProgress() < 1 {
+		return fmt.Errorf("track is buffered by %.0f%%", buffer.BufferingProgress()*100)
+	}
+	_, err := buffer.WriteTo(w)
+	return err
+}
+
+// verifyCache removes the cached tracks that don't match the size they were written with,
+// they are downloaded again by repairCache.
+func (m *Model) verifyCache() {
+	corrupt, err := cache.Verify(false)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to verify cache: %s", err)
+		return
+	}
+	if len(corrupt) == 0 {
+		return
+	}
+
+	ids := make([]string, len(corrupt))
+	for i, c := range corrupt {
+		log.Print(log.LVL_WARNIGN, "cached track [%s] is corrupt: %s", c.Track.Id, c.Reason)
+		ids[i] = c.Track.Id
+	}
+
+	err = cache.MarkBroken(ids...)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to remove corrupt cached tracks: %s", err)
+		m.tracker.ShowError("cache repair")
+	}
+}
+
+// repairCache downloads the corrupt tracks to the cache again.
+func (m *Model) repairCache() {
+	tracks, err := cache.BrokenTracks()
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to list corrupt cached tracks: %s", err)
+		return
+	}
+	m.downloadTracks(tracks)
+}
+
+// evictCache removes the least recently played tracks if the cache exceeds the size limit.
+// Liked tracks, tracks of the pinned and offline playlists and the playing track are kept.
+func (m *Model) evictCache() tea.Cmd {
+	if config.Current.CacheSizeLimit <= 0 {
+		return nil
+	}
+
+	pinnedTracks := make(map[string]bool)
+	for _, pl := range m.playlists.Items() {
+		pinned := pl.IsRemote() && m.followedPlaylists[followKey(pl.Uid, pl.RemoteKind)]
+		if !pinned && !pl.Offline {
+			continue
+		}
+		for i := range pl.Tracks {
+			pinnedTracks[pl.Tracks[i].Id] = true
+		}
+	}
+
+	var playingId string
+	if !m.tracker.IsStoped() {
+		playingId = m.tracker.CurrentTrack().Id
+	}
+
+	limit := int64(config.Current.CacheSizeLimit) * 1024 * 1024
+	evicted, err := cache.Evict(limit, func(trackId string) bool {
+		return trackId == playingId || m.likedTracksMap[trackId] || pinnedTracks[trackId]
+	})
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to evict cached tracks: %s", err)
+		m.tracker.ShowError("cache evict")
+	}
+	if len(evicted) == 0 {
+		return nil
+	}
+
+	log.Print(log.LVL_INFO, "evicted %d tracks from the cache", len(evicted))
+
+	cachePlaylist, index := m.playlists.GetFirst(playlist.LOCAL)
+	for _, trackId := range evicted {
+		delete(m.cachedTracksMap, trackId)
+		cachePlaylist.RemoveTrack(trackId)
+	}
+	cmd := m.playlists.SetItem(index, cachePlaylist)
+
+	if m.playlists.SelectedItem().Kind == playlist.LOCAL {
+		m.displayPlaylist(cachePlaylist)
+	}
+
 	m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
 	return cmd
 }
 
 func (m *Model) removeCache(track *api.Track) tea.Cmd {
+	if library.IsLocal(track) {
+		m.tracker.ShowError("local files are not removed")
+		return nil
+	}
 	if m.tracker.CurrentTrack().Id == track.Id && len(m.tracker.CurrentTrack().RealId) == 0 {
 		m.tracker.ShowError("can't remove currently playing track")
 		return nil
@@ -0,0 +1,181 @@
+package mainpage
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+// isDisliked reports whether the track or any of its artists is disliked.
+func (m *Model) isDisliked(track *api.Track) bool {
+	if m.dislikedTracksMap[track.Id] {
+		return true
+	}
+	for _, artist := range track.Artists {
+		if m.dislikedArtistsMap[artist.Id] {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlayable reports whether the track may be played when switching to the next or previous one.
+func (m *Model) isPlayable(track *api.Track) bool {
+	return track.Available && !m.isDisliked(track)
+}
+
+// dislikePlayingTrack toggles the playing track dislike and skips it if it became disliked.
+func (m *Model) dislikePlayingTrack() tea.Cmd {
+	if m.tracker.IsStoped() {
+		return nil
+	}
+
+	var currentPlaylist *playlist.Item
+	if m.currentPlaylistIndex >= 0 && m.playingFromPlaylist() {
+		currentPlaylist = m.playlists.Items()[m.currentPlaylistIndex]
+	}
+
+	track := *m.tracker.CurrentTrack()
+	cmd := m.dislikeTrack(&track, currentPlaylist)
+	if m.dislikedTracksMap[track.Id] {
+		m.nextTrack()
+	}
+	return cmd
+}
+
+func (m *Model) dislikeSelectedTrack() tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if len(selectedPlaylist.Tracks) == 0 {
+		return nil
+	}
+
+	track := m.tracklist.SelectedItem().Track
+	return m.dislikeTrack(track, selectedPlaylist)
+}
+
+func (m *Model) dislikeSelectedArtist() tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if len(selectedPlaylist.Tracks) == 0 {
+		return nil
+	}
+
+	track := m.tracklist.SelectedItem().Track
+	return m.dislikeArtist(track)
+}
+
+// dislikeTrack toggles the track dislike.
+// The disliked track is removed from likes and won't be played when switching tracks.
+func (m *Model) dislikeTrack(track *api.Track, pl *playlist.Item) tea.Cmd {
+	if m.client == nil || library.IsLocal(track) {
+		return nil
+	}
+
+	var (
+		cmds   []tea.Cmd
+		evType api.TrackEventType
+	)
+
+	dislikedPlaylist, index := m.playlists.GetFirst(playlist.DISLIKES)
+
+	if m.dislikedTracksMap[track.Id] {
+		err := m.client.UndislikeTrack(track.Id)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to undislike track [%s]: %s", track.Id, err)
+			m.tracker.ShowError("track undislike")
+			return nil
+		}
+		delete(m.dislikedTracksMap, track.Id)
+		dislikedPlaylist.RemoveTrack(track.Id)
+		evType = api.EV_TRACK_UNDISLIKED
+	} else {
+		err := m.client.DislikeTrack(track.Id)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to dislike track [%s]: %s", track.Id, err)
+			m.tracker.ShowError("track dislike")
+			return nil
+		}
+		m.dislikedTracksMap[track.Id] = true
+		dislikedPlaylist.AddTrack(track)
+		evType = api.EV_TRACK_DISLIKED
+		if m.likedTracksMap[track.Id] {
+			// the disliked track is removed from likes by the server
+			cmds = append(cmds, m.forgetLike(track))
+		}
+	}
+
+	if pl != nil && pl.Rotor {
+		var played float64
+		if currTrack := m.tracker.CurrentTrack(); currTrack != nil && currTrack.Id == track.Id {
+			played = m.tracker.Playtime().Seconds()
+		}
+		m.rotorFeedback(pl, api.NewTrackFeedbackEvent(evType, track, played))
+	}
+
+	cmds = append(cmds, m.playlists.SetItem(index, dislikedPlaylist))
+	if m.playlists.SelectedItem().Kind == playlist.DISLIKES {
+		m.displayPlaylist(dislikedPlaylist)
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// dislikeArtist toggles the dislike of the track main artist.
+// Tracks of the disliked artists won't be played when switching tracks.
+func (m *Model) dislikeArtist(track *api.Track) tea.Cmd {
+	if m.client == nil || len(track.Artists) == 0 {
+		return nil
+	}
+
+	artist := track.Artists[0]
+	if m.dislikedArtistsMap[artist.Id] {
+		err := m.client.UndislikeArtist(artist.Id)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to undislike artist [%d]: %s", artist.Id, err)
+			m.tracker.ShowError("artist undislike")
+			return nil
+		}
+		delete(m.dislikedArtistsMap, artist.Id)
+	} else {
+		err := m.client.DislikeArtist(artist.Id)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to dislike artist [%d]: %s", artist.Id, err)
+			m.tracker.ShowError("artist dislike")
+			return nil
+		}
+		m.dislikedArtistsMap[artist.Id] = true
+	}
+
+	if m.playlists.SelectedItem().Kind == playlist.DISLIKES {
+		m.displayPlaylist(m.playlists.SelectedItem())
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+
+	return nil
+}
+
+// forgetDislike removes the track from the local dislikes after it was liked.
+func (m *Model) forgetDislike(track *api.Track) tea.Cmd {
+	delete(m.dislikedTracksMap, track.Id)
+
+	dislikedPlaylist, index := m.playlists.GetFirst(playlist.DISLIKES)
+	dislikedPlaylist.RemoveTrack(track.Id)
+	cmd := m.playlists.SetItem(index, dislikedPlaylist)
+	if m.playlists.SelectedItem().Kind == playlist.DISLIKES {
+		m.displayPlaylist(dislikedPlaylist)
+	}
+
+	return cmd
+}
+
+func (m *Model) dislikesTitle() string {
+	title := "Disliked tracks"
+	if len(m.dislikedArtistsMap) > 0 {
+		title += fmt.Sprintf(" and %d artists", len(m.dislikedArtistsMap))
+	}
+	return title
+}
@@ -0,0 +1,238 @@
+package mainpage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bogem/id3v2/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/cache"
+	"github.com/dece2183/yamusic-tui/downloader"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/jobsview"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+const (
+	_DOWNLOAD_RETRIES = 2
+	_CACHE_TASK       = "cache"
+)
+
+type downloadMsg downloader.Result
+
+// offlineKey identifies the playlist in the offline set,
+// an empty key is returned for the playlists that can't be kept offline.
+func offlineKey(pl *playlist.Item) string {
+	switch {
+	case pl.Kind == playlist.LIKES:
+		return "likes"
+	case pl.IsRemote():
+		return followKey(pl.Uid, pl.RemoteKind)
+	case pl.Kind >= playlist.USER:
+		return fmt.Sprint(pl.Kind)
+	}
+	return ""
+}
+
+func (m *Model) cacheSelectedTracks() tea.Cmd {
+	m.downloadTracks(m.selectedTracks())
+	return nil
+}
+
+// downloadTracks queues the available tracks that are not cached yet to the background download.
+func (m *Model) downloadTracks(tracks []api.Track) {
+	if m.client == nil {
+		return
+	}
+
+	// the tracks are filtered in the copy, the caller's slice may be the playlist tracks
+	tracks = slices.DeleteFunc(slices.Clone(tracks), func(t api.Track) bool {
+		return !t.Available || m.cachedTracksMap[t.Id] || library.IsLocal(&t)
+	})
+	if len(tracks) == 0 {
+		return
+	}
+
+	if m.downloads.Add(_CACHE_TASK, m.writeCache, tracks...) > 0 {
+		m.showDownloadProgress()
+	}
+}
+
+// showDownloadProgress shows the progress of the unfinished downloads.
+func (m *Model) showDownloadProgress() {
+	done, _, total := m.downloads.Progress()
+	if done < total {
+		m.tracker.ShowProgress("downloading", done, total)
+	} else {
+		m.tracker.HideProgress()
+	}
+	m.refreshJobs()
+}
+
+func (m *Model) refreshJobs() {
+	m.jobsView.SetJobs(m.downloads.Jobs(), m.downloads.IsPaused())
+}
+
+// jobsControl applies the downloads view action to the selected job.
+func (m *Model) jobsControl(msg jobsview.Control) {
+	job, ok := m.jobsView.SelectedJob()
+
+	switch msg {
+	case jobsview.PAUSE:
+		if !ok {
+			break
+		}
+		if job.State == downloader.PAUSED {
+			m.downloads.Resume(job.Id)
+		} else {
+			m.downloads.Pause(job.Id)
+		}
+	case jobsview.PAUSE_ALL:
+		m.downloads.SetPaused(!m.downloads.IsPaused())
+	case jobsview.CANCEL:
+		if ok {
+			m.downloads.Cancel(job.Id)
+		}
+	case jobsview.RETRY:
+		if ok {
+			m.downloads.Retry(job.Id)
+		}
+	case jobsview.CLEAR:
+		m.downloads.ClearFinished()
+	}
+
+	m.showDownloadProgress()
+}
+
+// writeCache downloads the track with its cover and lyrics to the cache,
+// it's called from the download workers.
+func (m *Model) writeCache(ctx context.Context, track *api.Track, progress func(read, size int64)) error {
+	var cover bytes.Buffer
+	coverType, err := api.DownloadTrackCover(&cover, track, 200)
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "unable to download track [%s] cover: %s", track.Id, err)
+	}
+
+	trackReader, trackSize, err := m.downloadTrack(track)
+	if err != nil {
+		return err
+	}
+
+	defer trackReader.Close()
+
+	cacheFile, err := cache.Write(track)
+	if err != nil {
+		return err
+	}
+
+	defer cacheFile.Close()
+
+	tag := id3v2.NewEmptyTag()
+	setTrackTag(tag, track, coverType, cover.Bytes())
+	_, err = tag.WriteTo(cacheFile)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(cacheFile, downloader.NewReader(ctx, trackReader, trackSize, progress))
+	if err != nil {
+		return err
+	}
+	if trackSize > 0 && written != trackSize {
+		return fmt.Errorf("track is truncated to %d of %d bytes", written, trackSize)
+	}
+
+	err = cacheFile.Commit()
+	if err != nil {
+		return err
+	}
+
+	lrc, err := m.trackLyrics(track)
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "failed to obtain track [%s] lyrics: %s", track.Id, err)
+	}
+
+	cacheExtras(track, cover.Bytes(), lrc)
+	return nil
+}
+
+// trackDownloaded adds the downloaded track to the local playlist and updates the download progress.
+func (m *Model) trackDownloaded(msg downloadMsg) tea.Cmd {
+	var cmd tea.Cmd
+
+	m.refreshJobs()
+
+	job := &msg.Job
+	switch {
+	case job.State == downloader.FAILED:
+		log.Print(log.LVL_ERROR, "failed to download track [%s] to the %s: %s", job.Track.Id, job.Task, job.Err)
+	case job.State == downloader.QUEUED && job.Err != nil:
+		log.Print(log.LVL_WARNIGN, "retrying track [%s] download to the %s: %s", job.Track.Id, job.Task, job.Err)
+	case job.State == downloader.DONE && job.Task == _CACHE_TASK && !m.cachedTracksMap[job.Track.Id]:
+		m.cachedTracksMap[job.Track.Id] = true
+		cachePlaylist, index := m.playlists.GetFirst(playlist.LOCAL)
+		cachePlaylist.AddTrack(&job.Track)
+		cmd = m.playlists.SetItem(index, cachePlaylist)
+
+		if m.playlists.SelectedItem().Kind == playlist.LOCAL {
+			m.displayPlaylist(cachePlaylist)
+		}
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+
+	if msg.Done < msg.Total {
+		m.tracker.ShowProgress("downloading", msg.Done, msg.Total)
+		return cmd
+	}
+
+	if msg.Failed > 0 {
+		m.tracker.ShowError(fmt.Sprintf("failed to download %d tracks", msg.Failed))
+	}
+	m.tracker.HideProgress()
+	return tea.Batch(cmd, m.evictCache())
+}
+
+// toggleOffline keeps the selected playlist tracks downloaded to the cache,
+// or stops it if the playlist is already offline.
+// Already downloaded tracks stay in the cache.
+func (m *Model) toggleOffline() tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	key := offlineKey(selectedPlaylist)
+	if len(key) == 0 {
+		return nil
+	}
+
+	var err error
+	if m.offline.Contains(key) {
+		err = m.offline.Remove(key)
+	} else {
+		err = m.offline.Add(key)
+	}
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to save offline playlists: %s", err)
+		m.tracker.ShowError("offline playlists")
+	}
+
+	m.syncOffline(selectedPlaylist)
+	return m.playlists.SetItem(m.playlists.Index(), selectedPlaylist)
+}
+
+// syncOffline downloads the missing tracks of the playlist if it's kept offline.
+func (m *Model) syncOffline(pl *playlist.Item) {
+	pl.Offline = m.offline.Contains(offlineKey(pl))
+	if pl.Offline {
+		m.downloadTracks(pl.Tracks)
+	}
+}
+
+// syncOfflinePlaylists resumes the download of all offline playlists.
+func (m *Model) syncOfflinePlaylists() {
+	for _, pl := range m.playlists.Items() {
+		m.syncOffline(pl)
+	}
+}
@@ -0,0 +1,73 @@
+package mainpage
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/cache"
+	"github.com/dece2183/yamusic-tui/downloader"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+)
+
+const _EXPORT_TASK = "export"
+
+// exportSelectedTracks queues the selected tracks to be exported to the library in the background.
+func (m *Model) exportSelectedTracks() tea.Cmd {
+	tracks := m.selectedTracks()
+	available := tracks[:0]
+	for _, track := range streamedTracks(tracks) {
+		if m.cachedTracksMap[track.Id] || (track.Available && m.client != nil) {
+			available = append(available, track)
+		}
+	}
+
+	if m.downloads.Add(_EXPORT_TASK, m.writeExport, available...) > 0 {
+		m.showDownloadProgress()
+	}
+	return nil
+}
+
+// writeExport copies the cached track or downloads it to the library with the complete tag,
+// it's called from the download workers.
+func (m *Model) writeExport(ctx context.Context, track *api.Track, progress func(read, size int64)) error {
+	cover, err := cache.ReadCover(track.Id)
+	if err != nil && m.client != nil {
+		var buf bytes.Buffer
+		_, err = api.DownloadTrackCover(&buf, track, 400)
+		cover = buf.Bytes()
+	}
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "unable to obtain track [%s] cover: %s", track.Id, err)
+	}
+
+	lrc, err := m.trackLyrics(track)
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "failed to obtain track [%s] lyrics: %s", track.Id, err)
+	}
+
+	var (
+		trackReader io.ReadCloser
+		trackSize   int64
+	)
+	trackReader, trackSize, err = cache.Open(track.Id)
+	if err != nil {
+		trackReader, trackSize, err = m.downloadTrack(track)
+		if err != nil {
+			return err
+		}
+	}
+
+	defer trackReader.Close()
+
+	path, err := library.Write(track, downloader.NewReader(ctx, trackReader, trackSize, progress), trackSize, cover, lrc)
+	if err != nil {
+		return err
+	}
+
+	log.Print(log.LVL_INFO, "track [%s] is exported to [%s]", track.Id, path)
+	return nil
+}
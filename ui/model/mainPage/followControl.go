@@ -0,0 +1,268 @@
+package mainpage
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/input"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+const (
+	_PLAYLISTS_HEADER = "playlists:"
+	_FOLLOWED_HEADER  = "followed:"
+)
+
+func followKey(owner, kind uint64) string {
+	return fmt.Sprintf("%d:%d", owner, kind)
+}
+
+// remotePlaylistItem makes the sidebar item of the other user playlist.
+// Our own playlists are made as plain items, they are managed in the playlists section.
+func (m *Model) remotePlaylistItem(pl *api.Playlist, tracks []api.Track) *playlist.Item {
+	owner := pl.Owner.Name
+	if len(owner) == 0 {
+		owner = pl.Owner.Login
+	}
+
+	name := pl.Title + " by " + owner
+	if pl.Collective {
+		name += " (collective)"
+	}
+
+	item := &playlist.Item{
+		Name:       name,
+		Uid:        pl.Owner.Uid,
+		RemoteKind: pl.Kind,
+		Revision:   pl.Revision,
+		Seed:       playlistSeed(pl.Owner.Uid, pl.Kind),
+		Active:     true,
+		Subitem:    true,
+		Tracks:     tracks,
+	}
+	if m.client != nil && pl.Owner.Uid == m.client.UserId() {
+		item.Uid = 0
+		item.RemoteKind = 0
+	}
+	item.Offline = m.offline.Contains(offlineKey(item))
+	return item
+}
+
+// sectionEnd returns the index after the last item of the sidebar section with the header.
+// The sidebar end is returned if there is no such section.
+func (m *Model) sectionEnd(header string) int {
+	playlists := m.playlists.Items()
+
+	start := -1
+	for i, pl := range playlists {
+		if !pl.Active && !pl.Subitem && pl.Name == header {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return len(playlists)
+	}
+
+	end := start + 1
+	for end < len(playlists) && playlists[end].Subitem {
+		end++
+	}
+	return end
+}
+
+// loadFollowedPlaylists adds the followed playlists and the collective playlists of other users to the sidebar.
+// The collective playlists are not followed, so they are kept apart and can't be unpinned.
+func (m *Model) loadFollowedPlaylists(collective []api.Playlist) {
+	m.playlists.InsertItem(-1, &playlist.Item{Name: "", Kind: playlist.NONE, Active: false, Subitem: false})
+	m.playlists.InsertItem(-1, &playlist.Item{Name: _FOLLOWED_HEADER, Kind: playlist.NONE, Active: false, Subitem: false})
+
+	clear(m.followedPlaylists)
+	clear(m.collectivePlaylists)
+	if m.client == nil {
+		return
+	}
+
+	followed, err := m.client.LikedPlaylists()
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain followed playlists: %s", err)
+		m.tracker.ShowError("followed playlists")
+	}
+
+	for i, pl := range append(collective, followed...) {
+		key := followKey(pl.Owner.Uid, pl.Kind)
+		if m.followedPlaylists[key] || m.collectivePlaylists[key] {
+			continue
+		}
+
+		playlistTracks, err := m.client.PlaylistTracks(pl.Kind, pl.Owner.Uid, false)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to obtain playlist [%s] tracks: %s", pl.Title, err)
+			m.tracker.ShowError("playlist tracks")
+			continue
+		}
+
+		if i < len(collective) {
+			m.collectivePlaylists[key] = true
+		} else {
+			m.followedPlaylists[key] = true
+		}
+		m.playlists.InsertItem(-1, m.remotePlaylistItem(&pl, playlistTracks))
+	}
+}
+
+func (m *Model) showOpenUserDialog() {
+	m.inputDialog.Title = "Open playlists of user (login or uid)"
+	m.inputDialog.Action = "open"
+	m.inputDialog.Info = ""
+	m.inputDialog.ToggleAction = ""
+	m.inputDialog.SetValue("")
+	m.isOpenUserActive = true
+}
+
+func (m *Model) openUserControl(msg input.Control) tea.Cmd {
+	m.isOpenUserActive = false
+	if msg != input.APPLY {
+		return nil
+	}
+
+	user := strings.TrimSpace(m.inputDialog.Value())
+	if len(user) == 0 {
+		return nil
+	}
+
+	return m.openUser(user)
+}
+
+// openUser displays the public playlists of the user in the sidebar results.
+func (m *Model) openUser(user string) tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+
+	playlists, err := m.client.UserPlaylists(user)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain user [%s] playlists: %s", user, err)
+		m.tracker.ShowError("user playlists")
+		return nil
+	}
+
+	items := make([]*playlist.Item, 0, len(playlists))
+	for _, pl := range playlists {
+		if pl.Visibility == "private" || pl.TrackCount == 0 {
+			continue
+		}
+
+		playlistTracks, err := m.client.PlaylistTracks(pl.Kind, pl.Owner.Uid, false)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to obtain user [%s] playlist [%s] tracks: %s", user, pl.Title, err)
+			m.tracker.ShowError("user playlist tracks")
+			continue
+		}
+
+		items = append(items, m.remotePlaylistItem(&pl, playlistTracks))
+	}
+
+	return m.displayResults(user+" playlists:", items)
+}
+
+// togglePin follows the selected playlist of the other user and pins it to the sidebar,
+// or unfollows and unpins it if it is already followed.
+func (m *Model) togglePin() tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if m.client == nil || !selectedPlaylist.IsRemote() {
+		return nil
+	}
+
+	key := followKey(selectedPlaylist.Uid, selectedPlaylist.RemoteKind)
+	if m.collectivePlaylists[key] {
+		m.tracker.ShowError("collective playlists can't be unpinned")
+		return nil
+	}
+	if m.followedPlaylists[key] {
+		err := m.client.UnlikePlaylist(selectedPlaylist.Uid, selectedPlaylist.RemoteKind)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to unfollow playlist [%s]: %s", selectedPlaylist.Name, err)
+			m.tracker.ShowError("playlist unfollow")
+			return nil
+		}
+		delete(m.followedPlaylists, key)
+		m.unpinPlaylist(key)
+		return nil
+	}
+
+	err := m.client.LikePlaylist(selectedPlaylist.Uid, selectedPlaylist.RemoteKind)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to follow playlist [%s]: %s", selectedPlaylist.Name, err)
+		m.tracker.ShowError("playlist follow")
+		return nil
+	}
+	m.followedPlaylists[key] = true
+
+	index := m.sectionEnd(_FOLLOWED_HEADER)
+	cmd := m.playlists.InsertItem(index, &playlist.Item{
+		Name:       selectedPlaylist.Name,
+		Uid:        selectedPlaylist.Uid,
+		RemoteKind: selectedPlaylist.RemoteKind,
+		Revision:   selectedPlaylist.Revision,
+		Seed:       selectedPlaylist.Seed,
+		Active:     true,
+		Subitem:    true,
+		Offline:    selectedPlaylist.Offline,
+		Tracks:     selectedPlaylist.Tracks,
+	})
+	if m.currentPlaylistIndex >= index {
+		m.currentPlaylistIndex++
+	}
+	if m.playlists.Index() >= index {
+		m.playlists.Select(m.playlists.Index() + 1)
+	}
+
+	return cmd
+}
+
+// unpinPlaylist removes the followed playlist from the sidebar section.
+func (m *Model) unpinPlaylist(key string) {
+	if m.collectivePlaylists[key] {
+		return
+	}
+
+	end := m.sectionEnd(_FOLLOWED_HEADER)
+	playlists := m.playlists.Items()
+
+	index := -1
+	for i := end - 1; i >= 0 && playlists[i].Subitem; i-- {
+		if playlists[i].IsRemote() && followKey(playlists[i].Uid, playlists[i].RemoteKind) == key {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	if m.currentPlaylistIndex == index {
+		m.currentPlaylistIndex = -1
+	} else if m.currentPlaylistIndex > index {
+		m.currentPlaylistIndex--
+	}
+
+	selected := m.playlists.Index()
+	m.playlists.RemoveItem(index)
+	if selected == index {
+		// the unpinned playlist was selected, select the nearest one above
+		playlists = m.playlists.Items()
+		selected = min(selected, len(playlists)-1)
+		for selected > 0 && !playlists[selected].Active {
+			selected--
+		}
+		m.playlists.Select(selected)
+		m.displayPlaylist(m.playlists.SelectedItem())
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	} else if selected > index {
+		m.playlists.Select(selected - 1)
+	}
+}
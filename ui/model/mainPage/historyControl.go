@@ -0,0 +1,134 @@
+package mainpage
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/history"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+// historyTracks returns the played tracks from the newest to the oldest.
+func (m *Model) historyTracks() []api.Track {
+	entries := m.history.Entries()
+	tracks := make([]api.Track, len(entries))
+	for i := range entries {
+		tracks[len(entries)-1-i] = entries[i].Track
+	}
+	return tracks
+}
+
+// playbackInfo is the started track playback that is reported when it stops.
+type playbackInfo struct {
+	entry     history.Entry
+	playId    string
+	fromCache bool
+}
+
+// startPlayback remembers the started track to write it to the history when it stops.
+func (m *Model) startPlayback(track *api.Track, fromCache bool) {
+	var source string
+	switch {
+	case m.playingQueued:
+		source = "queue"
+	case m.historyPos >= 0:
+		source = "history"
+	case m.currentPlaylistIndex >= 0:
+		source = m.playlists.Items()[m.currentPlaylistIndex].Name
+	}
+
+	m.playback = &playbackInfo{
+		entry: history.Entry{
+			Time:   time.Now(),
+			Source: source,
+			Track:  *track,
+		},
+		playId:    api.NewPlayId(),
+		fromCache: fromCache,
+	}
+	m.scrobbler.NowPlaying(track)
+}
+
+// recordPlayback reports the last started track playback to the server,
+// writes it to the history and scrobbles it.
+func (m *Model) recordPlayback() tea.Cmd {
+	if m.playback == nil {
+		return nil
+	}
+
+	playback := m.playback
+	entry := playback.entry
+	played := m.tracker.Playtime()
+	entry.Played = played.Seconds()
+	m.playback = nil
+
+	if m.client != nil && !library.IsLocal(&entry.Track) {
+		client := m.client
+		position := m.tracker.Position().Seconds()
+		m.reports.Add(1)
+		go func() {
+			defer m.reports.Done()
+			err := client.PlayTrack(&entry.Track, playback.playId, entry.Played, position, playback.fromCache)
+			if err != nil {
+				log.Print(log.LVL_WARNIGN, "failed to report track [%s] playback: %s", entry.Track.Id, err)
+			}
+		}()
+	}
+
+	m.scrobbler.Scrobble(&entry.Track, entry.Time, played)
+
+	historyLen := m.history.Len()
+	err := m.history.Add(entry)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to write playback history: %s", err)
+		m.tracker.ShowError("playback history")
+	}
+	if m.historyPos >= 0 {
+		// the oldest entries dropped from the history shift the position
+		m.historyPos = max(m.historyPos-(historyLen+1-m.history.Len()), 0)
+	}
+
+	historyPlaylist, index := m.playlists.GetFirst(playlist.HISTORY)
+	if historyPlaylist == nil {
+		return nil
+	}
+
+	historyPlaylist.AddTrack(&entry.Track)
+	cmd := m.playlists.SetItem(index, historyPlaylist)
+
+	if m.playlists.SelectedItem().Kind == playlist.HISTORY {
+		m.displayPlaylist(historyPlaylist)
+	}
+
+	return cmd
+}
+
+// playPrevFromHistory plays the track that was played before the current one.
+// Repeated calls go further back in the history.
+func (m *Model) playPrevFromHistory() bool {
+	entries := m.history.Entries()
+
+	pos := m.historyPos
+	if pos < 0 {
+		pos = len(entries)
+	}
+
+	pos--
+	for pos >= 0 && !m.isPlayable(&entries[pos].Track) {
+		pos--
+	}
+	if pos < 0 {
+		return false
+	}
+
+	m.indicateCurrentTrackPlaying(false)
+	m.playingQueued = false
+	m.historyPos = pos
+
+	track := entries[pos].Track
+	m.playTrack(&track)
+	return true
+}
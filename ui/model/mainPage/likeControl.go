@@ -1,15 +1,16 @@
 package mainpage
 
 import (
+	"slices"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dece2183/yamusic-tui/api"
-	"github.com/dece2183/yamusic-tui/log"
 	"github.com/dece2183/yamusic-tui/ui/components/playlist"
 )
 
 func (m *Model) likePlayingTrack() tea.Cmd {
 	var currentPlaylist *playlist.Item
-	if m.currentPlaylistIndex >= 0 {
+	if m.currentPlaylistIndex >= 0 && m.playingFromPlaylist() {
 		currentPlaylist = m.playlists.Items()[m.currentPlaylistIndex]
 	}
 
@@ -17,7 +18,7 @@ func (m *Model) likePlayingTrack() tea.Cmd {
 	return m.likeTrack(track, currentPlaylist)
 }
 
-func (m *Model) likeSelectedTrack() tea.Cmd {
+func (m *Model) likeSelectedTracks() tea.Cmd {
 	if m.currentPlaylistIndex < 0 {
 		return nil
 	}
@@ -27,41 +28,88 @@ func (m *Model) likeSelectedTrack() tea.Cmd {
 		return nil
 	}
 
-	track := m.tracklist.SelectedItem().Track
-	return m.likeTrack(track, selectedPlaylist)
+	return m.likeTracks(m.selectedTracks(), selectedPlaylist)
 }
 
 func (m *Model) likeTrack(track *api.Track, pl *playlist.Item) tea.Cmd {
+	return m.likeTracks([]api.Track{*track}, pl)
+}
+
+// likeTracks likes the tracks with a single request,
+// if all of them are already liked, they are unliked instead.
+func (m *Model) likeTracks(tracks []api.Track, pl *playlist.Item) tea.Cmd {
+	tracks = streamedTracks(tracks)
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	like := slices.ContainsFunc(tracks, func(t api.Track) bool { return !m.likedTracksMap[t.Id] })
+	tracks = slices.DeleteFunc(tracks, func(t api.Track) bool { return m.likedTracksMap[t.Id] == like })
+
+	ids := make([]string, len(tracks))
+	for i := range tracks {
+		ids[i] = tracks[i].Id
+	}
+
 	likedPlaylist, index := m.playlists.GetFirst(playlist.LIKES)
 
-	var evType api.TrackEventType
-	if m.likedTracksMap[track.Id] {
-		if m.client.UnlikeTrack(track.Id) != nil {
+	var (
+		cmds   []tea.Cmd
+		evType api.TrackEventType
+	)
+
+	if like {
+		if m.client.LikeTracks(ids) != nil {
 			return nil
 		}
-		delete(m.likedTracksMap, track.Id)
-		likedPlaylist.RemoveTrack(track.Id)
-		evType = api.EV_TRACK_UNLIKED
+		// add in reverse, so the first track ends up on top
+		for i := len(tracks) - 1; i >= 0; i-- {
+			track := &tracks[i]
+			m.likedTracksMap[track.Id] = true
+			likedPlaylist.AddTrack(track)
+			if m.dislikedTracksMap[track.Id] {
+				// the liked track is removed from dislikes by the server
+				cmds = append(cmds, m.forgetDislike(track))
+			}
+		}
+		evType = api.EV_TRACK_LIKED
+		m.syncOffline(likedPlaylist)
 	} else {
-		if m.client.LikeTrack(track.Id) != nil {
+		if m.client.UnlikeTracks(ids) != nil {
 			return nil
 		}
-		m.likedTracksMap[track.Id] = true
-		likedPlaylist.AddTrack(track)
-		evType = api.EV_TRACK_LIKED
+		for _, track := range tracks {
+			delete(m.likedTracksMap, track.Id)
+			likedPlaylist.RemoveTrack(track.Id)
+		}
+		evType = api.EV_TRACK_UNLIKED
 	}
 
 	if pl != nil && pl.Rotor {
-		ev := api.NewTrackFeedbackEvent(evType, track, 0)
-		go m.client.RotorSessionFeedback(pl.SessionId, api.NewFeedback(pl.SessionBatch, ev))
-		log.Print(log.LVL_INFO, "feedback event sended: "+ev.Type+" track: "+track.Title)
+		for i := range tracks {
+			m.rotorFeedback(pl, api.NewTrackFeedbackEvent(evType, &tracks[i], 0))
+		}
 	}
 
-	cmd := m.playlists.SetItem(index, likedPlaylist)
+	cmds = append(cmds, m.playlists.SetItem(index, likedPlaylist))
 	if m.playlists.SelectedItem().Kind == playlist.LIKES {
 		m.displayPlaylist(likedPlaylist)
 	}
 
 	m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	return tea.Batch(cmds...)
+}
+
+// forgetLike removes the track from the local likes after it was disliked.
+func (m *Model) forgetLike(track *api.Track) tea.Cmd {
+	delete(m.likedTracksMap, track.Id)
+
+	likedPlaylist, index := m.playlists.GetFirst(playlist.LIKES)
+	likedPlaylist.RemoveTrack(track.Id)
+	cmd := m.playlists.SetItem(index, likedPlaylist)
+	if m.playlists.SelectedItem().Kind == playlist.LIKES {
+		m.displayPlaylist(likedPlaylist)
+	}
+
 	return cmd
 }
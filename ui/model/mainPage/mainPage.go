@@ -1,19 +1,29 @@
 package mainpage
 
 import (
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/cache"
 	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/downloader"
+	"github.com/dece2183/yamusic-tui/history"
 	"github.com/dece2183/yamusic-tui/log"
 	"github.com/dece2183/yamusic-tui/media/handler"
+	"github.com/dece2183/yamusic-tui/offline"
+	"github.com/dece2183/yamusic-tui/queue"
+	"github.com/dece2183/yamusic-tui/scrobbler"
 	"github.com/dece2183/yamusic-tui/ui/components/input"
+	"github.com/dece2183/yamusic-tui/ui/components/jobsview"
 	"github.com/dece2183/yamusic-tui/ui/components/playlist"
 	"github.com/dece2183/yamusic-tui/ui/components/search"
+	"github.com/dece2183/yamusic-tui/ui/components/statsview"
 	"github.com/dece2183/yamusic-tui/ui/components/tracker"
 	"github.com/dece2183/yamusic-tui/ui/components/tracklist"
 	"github.com/dece2183/yamusic-tui/ui/model"
@@ -31,6 +41,9 @@ const (
 	LOADING_DONE LoadingMsg = iota
 )
 
+type setRepeatMsg config.RepeatMode
+type setShuffleMsg bool
+
 type Model struct {
 	program       *tea.Program
 	client        *api.YaMusicClient
@@ -42,34 +55,86 @@ type Model struct {
 	playlists *playlist.Model
 	tracklist *tracklist.Model
 	tracker   *tracker.Model
+	statsView *statsview.Model
+	jobsView  *jobsview.Model
 
 	searchDialog           *search.Model
 	inputDialog            *input.Model
 	isLoading              bool
 	isSearchActive         bool
 	isAddPlaylistActive    bool
+	isWaveActive           bool
 	isRenamePlaylistActive bool
+	isEditPlaylistActive   bool
+	isOpenUserActive       bool
+	isExportPlaylistActive bool
+	isImportPlaylistActive bool
+	isReviewImportActive   bool
 	isPlaylistHideOverride bool
 
+	queue                *queue.Queue
+	playingQueued        bool
+	resumeRotor          bool
+	history              *history.History
+	historyPos           int
+	playback             *playbackInfo
+	playingLyrics        string
+	reports              sync.WaitGroup
+	scrobbler            *scrobbler.Scrobbler
 	currentPlaylistIndex int
+	waveSeeds            []waveSeed
+	importing            *playlistImport
+	editedPlaylist       api.Playlist
 	likedTracksMap       map[string]bool
+	dislikedTracksMap    map[string]bool
+	dislikedArtistsMap   map[uint64]bool
 	cachedTracksMap      map[string]bool
+	followedPlaylists    map[string]bool
+	collectivePlaylists  map[string]bool
+	downloads            *downloader.Manager
+	offline              *offline.Set
 }
 
 // mainpage.Model constructor.
 func New(mediaHandler handler.MediaHandler) *Model {
+	var err error
 	m := &Model{}
 
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	m.program = p
 	m.clipboard = clipboard.New()
 	m.mediaHandler = mediaHandler
+	m.queue = queue.New()
+	m.historyPos = -1
+	m.history, err = history.Load()
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to load playback history: %s", err)
+	}
+	m.scrobbler = scrobbler.New()
+	m.scrobbler.Flush()
 	m.likedTracksMap = make(map[string]bool)
+	m.dislikedTracksMap = make(map[string]bool)
+	m.dislikedArtistsMap = make(map[uint64]bool)
 	m.cachedTracksMap = make(map[string]bool)
+	m.followedPlaylists = make(map[string]bool)
+	m.collectivePlaylists = make(map[string]bool)
+	err = cache.ClearTemp()
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to clear temporary cache files: %s", err)
+	}
+	m.offline, err = offline.Load()
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to load offline playlists: %s", err)
+	}
+	m.downloads = downloader.New(config.Current.DownloadJobs, _DOWNLOAD_RETRIES, func(res downloader.Result) {
+		m.program.Send(downloadMsg(res))
+	})
 	m.spinner = spinner.New(spinner.WithSpinner(spinner.Points))
 	m.playlists = playlist.New(m.program, "YaMusic")
-	m.tracklist = tracklist.New(m.program, &m.likedTracksMap, &m.cachedTracksMap)
+	m.tracklist = tracklist.New(m.program, &m.likedTracksMap, &m.cachedTracksMap, m.isDisliked)
 	m.tracker = tracker.New(m.program, &m.likedTracksMap)
+	m.statsView = statsview.New()
+	m.jobsView = jobsview.New()
 	m.searchDialog = search.New()
 	m.inputDialog = input.New()
 
@@ -83,7 +148,18 @@ func New(mediaHandler handler.MediaHandler) *Model {
 func (m *Model) Run() error {
 	go m.mediaHandle()
 	_, err := m.program.Run()
+	if m.currentPlaylistIndex >= 0 {
+		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
+		if currentPlaylist.Rotor {
+			if m.playingFromPlaylist() && !m.tracker.IsStoped() {
+				m.rotorFeedback(currentPlaylist, m.trackFeedbackEvent())
+			}
+			m.finishRotor(currentPlaylist)
+		}
+	}
+	m.recordPlayback()
 	m.tracker.Stop()
+	m.reports.Wait()
 	return err
 }
 
@@ -110,7 +186,40 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := message.(type) {
 	case LoadingMsg:
 		m.isLoading = false
-		return m, model.Cmd(playlist.CURSOR_UP)
+		m.syncOfflinePlaylists()
+		m.repairCache()
+		return m, tea.Batch(m.evictCache(), model.Cmd(playlist.CURSOR_UP))
+
+	case rotorTracksMsg:
+		cmd = m.appendRotorTracks(msg)
+		cmds = append(cmds, cmd)
+
+	case downloadMsg:
+		cmd = m.trackDownloaded(msg)
+		cmds = append(cmds, cmd)
+
+	case importProgressMsg:
+		m.tracker.ShowProgress("importing", msg.done, msg.total)
+
+	case importMsg:
+		m.showDownloadProgress()
+		m.importing = msg
+		cmd = m.reviewImport()
+		cmds = append(cmds, cmd)
+
+	case setRepeatMsg:
+		m.tracker.SetRepeat(config.RepeatMode(msg))
+		m.updatePlaybackOptions()
+
+	case setShuffleMsg:
+		if m.currentPlaylistIndex < 0 {
+			break
+		}
+		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
+		if currentPlaylist.Kind >= playlist.LIKES && len(currentPlaylist.Tracks) > 0 {
+			cmd = m.shufflePlaylist(currentPlaylist, bool(msg))
+			cmds = append(cmds, cmd)
+		}
 
 	case tea.WindowSizeMsg:
 		m.resize(msg.Width, msg.Height)
@@ -123,10 +232,10 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case controls.Quit.Contains(keypress):
 			return m, tea.Quit
-		case m.isSearchActive || m.isAddPlaylistActive:
+		case m.isSearchActive || m.isAddPlaylistActive || m.isWaveActive || m.isReviewImportActive:
 			m.searchDialog, cmd = m.searchDialog.Update(message)
 			cmds = append(cmds, cmd)
-		case m.isRenamePlaylistActive:
+		case m.isRenamePlaylistActive || m.isEditPlaylistActive || m.isOpenUserActive || m.isExportPlaylistActive || m.isImportPlaylistActive:
 			m.inputDialog, cmd = m.inputDialog.Update(message)
 			cmds = append(cmds, cmd)
 		case controls.Reload.Contains(keypress):
@@ -142,7 +251,14 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.playlists, cmd = m.playlists.Update(message)
 				cmds = append(cmds, cmd)
-				m.tracklist, cmd = m.tracklist.Update(message)
+				selectedKind := m.playlists.SelectedItem().Kind
+				if selectedKind == playlist.STATS && !controls.TracksHide.Contains(keypress) {
+					m.statsView, cmd = m.statsView.Update(message)
+				} else if selectedKind == playlist.DOWNLOADS && !controls.TracksHide.Contains(keypress) {
+					m.jobsView, cmd = m.jobsView.Update(message)
+				} else {
+					m.tracklist, cmd = m.tracklist.Update(message)
+				}
 				cmds = append(cmds, cmd)
 				m.tracker, cmd = m.tracker.Update(message)
 				cmds = append(cmds, cmd)
@@ -165,16 +281,40 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 
 			m.displayPlaylist(selectedPlaylist)
 			m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+			if selectedPlaylist.Kind == playlist.STATS {
+				m.statsView.Refresh()
+			}
+			if selectedPlaylist.Kind == playlist.DOWNLOADS {
+				m.refreshJobs()
+			}
 
-			m.tracklist.Shufflable = (selectedPlaylist.Kind != playlist.NONE && selectedPlaylist.Kind != playlist.MYWAVE && len(selectedPlaylist.Tracks) > 0)
+			m.tracklist.Shufflable = (selectedPlaylist.Kind != playlist.NONE && !selectedPlaylist.Rotor && selectedPlaylist.Kind != playlist.QUEUE && len(selectedPlaylist.Tracks) > 0)
+			m.tracklist.Reorderable = selectedPlaylist.Kind == playlist.QUEUE || selectedPlaylist.Kind >= playlist.USER
 		case playlist.RENAME:
 			selectedPlaylist := m.playlists.SelectedItem()
 			if selectedPlaylist.Kind < playlist.USER {
 				break
 			}
 			m.inputDialog.Title = "Rename playlist " + selectedPlaylist.Name
+			m.inputDialog.Action = "apply"
+			m.inputDialog.Info = ""
+			m.inputDialog.ToggleAction = ""
 			m.inputDialog.SetValue(selectedPlaylist.Name)
 			m.isRenamePlaylistActive = true
+		case playlist.EDIT:
+			m.showEditPlaylistDialog()
+		case playlist.OPEN_USER:
+			m.showOpenUserDialog()
+		case playlist.PIN:
+			cmd = m.togglePin()
+			cmds = append(cmds, cmd)
+		case playlist.OFFLINE:
+			cmd = m.toggleOffline()
+			cmds = append(cmds, cmd)
+		case playlist.EXPORT:
+			m.showExportPlaylistDialog()
+		case playlist.IMPORT:
+			m.showImportPlaylistDialog()
 		case playlist.TOGGLE_VIEW:
 			m.isPlaylistHideOverride = !m.isPlaylistHideOverride
 		}
@@ -187,6 +327,10 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 			if !playlistItem.Active {
 				break
 			}
+			if playlistItem.Kind == playlist.QUEUE {
+				m.playQueued(m.tracklist.Index())
+				break
+			}
 			m.playSelectedPlaylist(m.tracklist.Index())
 		case tracklist.CURSOR_UP, tracklist.CURSOR_DOWN:
 			currentPlaylist := m.playlists.SelectedItem()
@@ -195,36 +339,91 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.playlists.SetItem(m.playlists.Index(), currentPlaylist)
 			cmds = append(cmds, cmd)
 		case tracklist.LIKE:
-			cmd = m.likeSelectedTrack()
+			cmd = m.likeSelectedTracks()
+			cmds = append(cmds, cmd)
+		case tracklist.DISLIKE:
+			cmd = m.dislikeSelectedTrack()
 			cmds = append(cmds, cmd)
+		case tracklist.DISLIKE_ARTIST:
+			cmd = m.dislikeSelectedArtist()
+			cmds = append(cmds, cmd)
+		case tracklist.WAVE:
+			m.showWaveDialog()
 		case tracklist.ADD_TO_PLAYLIST:
-			selectedTrack := m.tracklist.SelectedItem()
-			m.searchDialog.Title = "Add " + selectedTrack.Track.Title + " to"
+			if len(m.tracklist.Items()) == 0 {
+				break
+			}
+			if marked := len(m.tracklist.MarkedIndexes()); marked > 0 {
+				m.searchDialog.Title = fmt.Sprintf("Add %d tracks to", marked)
+			} else {
+				m.searchDialog.Title = "Add " + m.tracklist.SelectedItem().Track.Title + " to"
+			}
 			m.searchDialog.Action = "add"
 			m.isAddPlaylistActive = true
 			m.Send(search.UPDATE_SUGGESTIONS)
 		case tracklist.REMOVE_FROM_PLAYLIST:
 			selectedPlaylist := m.playlists.SelectedItem()
-			cmd = m.removeFromPlaylist(selectedPlaylist, m.tracklist.Index())
+			cmd = m.removeFromPlaylist(selectedPlaylist, m.selectedIndexes())
+			cmds = append(cmds, cmd)
+		case tracklist.ADD_TO_QUEUE:
+			cmd = m.queueSelectedTracks(false)
 			cmds = append(cmds, cmd)
+		case tracklist.PLAY_NEXT:
+			cmd = m.queueSelectedTracks(true)
+			cmds = append(cmds, cmd)
+		case tracklist.QUEUE_ALL:
+			cmd = m.queueSelectedPlaylist()
+			cmds = append(cmds, cmd)
+		case tracklist.MOVE_UP:
+			selectedPlaylist := m.playlists.SelectedItem()
+			if selectedPlaylist.Kind == playlist.QUEUE {
+				cmd = m.moveInQueue(m.tracklist.Index(), -1)
+				cmds = append(cmds, cmd)
+			} else if selectedPlaylist.Kind >= playlist.USER {
+				cmd = m.moveInPlaylist(selectedPlaylist, m.selectedIndexes(), -1)
+				cmds = append(cmds, cmd)
+			}
+		case tracklist.MOVE_DOWN:
+			selectedPlaylist := m.playlists.SelectedItem()
+			if selectedPlaylist.Kind == playlist.QUEUE {
+				cmd = m.moveInQueue(m.tracklist.Index(), 1)
+				cmds = append(cmds, cmd)
+			} else if selectedPlaylist.Kind >= playlist.USER {
+				cmd = m.moveInPlaylist(selectedPlaylist, m.selectedIndexes(), 1)
+				cmds = append(cmds, cmd)
+			}
 		case tracklist.SEARCH:
 			m.searchDialog.Title = "Search"
 			m.searchDialog.Action = "search"
 			m.isSearchActive = true
 			m.Send(search.UPDATE_SUGGESTIONS)
 		case tracklist.SHUFFLE:
-			cmd = m.shufflePlaylist(m.playlists.SelectedItem())
+			selectedPlaylist := m.playlists.SelectedItem()
+			cmd = m.shufflePlaylist(selectedPlaylist, !selectedPlaylist.Shuffled)
 			cmds = append(cmds, cmd)
 		case tracklist.SHARE:
-			link := api.ShareTrackLink(m.tracklist.SelectedItem().Track)
-			if link != "" {
-				m.clipboard.CopyText(link)
+			var links []string
+			for _, track := range streamedTracks(m.selectedTracks()) {
+				if link := api.ShareTrackLink(&track); link != "" {
+					links = append(links, link)
+				}
 			}
+			if len(links) > 0 {
+				m.clipboard.CopyText(strings.Join(links, "\n"))
+			}
+		case tracklist.CACHE:
+			cmd = m.cacheSelectedTracks()
+			cmds = append(cmds, cmd)
+		case tracklist.EXPORT:
+			cmd = m.exportSelectedTracks()
+			cmds = append(cmds, cmd)
 		}
 
 	// player control update
 	case tracker.Control:
 		switch msg {
+		case tracker.TRACK_ENDED:
+			m.trackEnded()
 		case tracker.NEXT:
 			m.nextTrack()
 		case tracker.PREV:
@@ -232,14 +431,22 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		case tracker.LIKE:
 			cmd = m.likePlayingTrack()
 			cmds = append(cmds, cmd)
+		case tracker.DISLIKE:
+			cmd = m.dislikePlayingTrack()
+			cmds = append(cmds, cmd)
 		case tracker.PLAY, tracker.PAUSE:
 			m.mediaHandler.OnPlayPause()
 		case tracker.STOP:
+			// the playback is recorded before the tracker closes the track and resets its position
+			cmd = m.recordPlayback()
+			cmds = append(cmds, cmd)
 			m.mediaHandler.OnEnded()
 		case tracker.REWIND:
 			m.mediaHandler.OnSeek(m.tracker.Position())
 		case tracker.VOLUME:
 			m.mediaHandler.OnVolume()
+		case tracker.REPEAT:
+			m.updatePlaybackOptions()
 		case tracker.CACHE_TRACK:
 			cmd = m.cacheCurrentTrack()
 			cmds = append(cmds, cmd)
@@ -262,10 +469,40 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		} else if m.isAddPlaylistActive {
 			cmd = m.addPlaylistControl(msg)
 			cmds = append(cmds, cmd)
+		} else if m.isWaveActive {
+			cmd = m.waveControl(msg)
+			cmds = append(cmds, cmd)
+		} else if m.isReviewImportActive {
+			cmd = m.reviewImportControl(msg)
+			cmds = append(cmds, cmd)
 		}
 
+	// downloads view control update
+	case jobsview.Control:
+		m.jobsControl(msg)
+
 	// input dialog control update
 	case input.Control:
+		if m.isEditPlaylistActive {
+			cmd = m.editPlaylistControl(msg)
+			cmds = append(cmds, cmd)
+			break
+		}
+		if m.isOpenUserActive {
+			cmd = m.openUserControl(msg)
+			cmds = append(cmds, cmd)
+			break
+		}
+		if m.isExportPlaylistActive {
+			cmd = m.exportPlaylistControl(msg)
+			cmds = append(cmds, cmd)
+			break
+		}
+		if m.isImportPlaylistActive {
+			cmd = m.importPlaylistControl(msg)
+			cmds = append(cmds, cmd)
+			break
+		}
 		m.isRenamePlaylistActive = false
 		cmd = m.renamePlaylistControl(msg)
 		cmds = append(cmds, cmd)
@@ -274,10 +511,10 @@ func (m *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		if m.isLoading {
 			m.spinner, cmd = m.spinner.Update(message)
 			cmds = append(cmds, cmd)
-		} else if m.isSearchActive || m.isAddPlaylistActive {
+		} else if m.isSearchActive || m.isAddPlaylistActive || m.isWaveActive || m.isReviewImportActive {
 			m.searchDialog, cmd = m.searchDialog.Update(message)
 			cmds = append(cmds, cmd)
-		} else if m.isRenamePlaylistActive {
+		} else if m.isRenamePlaylistActive || m.isEditPlaylistActive || m.isOpenUserActive || m.isExportPlaylistActive || m.isImportPlaylistActive {
 			m.inputDialog, cmd = m.inputDialog.Update(message)
 			cmds = append(cmds, cmd)
 		} else {
@@ -298,9 +535,9 @@ func (m *Model) View() string {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.spinner.View())
 	}
 
-	if m.isSearchActive || m.isAddPlaylistActive {
+	if m.isSearchActive || m.isAddPlaylistActive || m.isWaveActive || m.isReviewImportActive {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.searchDialog.View())
-	} else if m.isRenamePlaylistActive {
+	} else if m.isRenamePlaylistActive || m.isEditPlaylistActive || m.isOpenUserActive || m.isExportPlaylistActive || m.isImportPlaylistActive {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.inputDialog.View())
 	}
 
@@ -309,12 +546,23 @@ func (m *Model) View() string {
 
 	m.tracker.SetWidth(m.width - playlistWidth - 2)
 	m.tracklist.SetWidth(m.width - playlistWidth - 2)
+	m.statsView.SetWidth(m.width - playlistWidth - 2)
+	m.jobsView.SetWidth(m.width - playlistWidth - 2)
 
 	trackerView := m.tracker.View()
 	trackerHeight := lipgloss.Height(trackerView)
 	m.tracklist.SetHeight(m.height - trackerHeight - 2)
-
-	tracklistView := m.tracklist.View()
+	m.statsView.SetHeight(m.height - trackerHeight - 2)
+	m.jobsView.SetHeight(m.height - trackerHeight - 2)
+
+	var tracklistView string
+	if m.playlists.SelectedItem().Kind == playlist.STATS && !m.tracklist.Hidden {
+		tracklistView = m.statsView.View()
+	} else if m.playlists.SelectedItem().Kind == playlist.DOWNLOADS && !m.tracklist.Hidden {
+		tracklistView = m.jobsView.View()
+	} else {
+		tracklistView = m.tracklist.View()
+	}
 
 	var midPanel string
 	if m.tracklist.Hidden {
@@ -387,7 +635,11 @@ func (m *Model) initialLoad() {
 			station.StationId = session.Id
 			station.SessionId = session.RadioSessionId
 			station.SessionBatch = session.BatchId
-			station.Tracks = []api.Track{session.Sequence[0].Track}
+			station.Refilling = false
+			station.Tracks = make([]api.Track, len(session.Sequence))
+			for t := range session.Sequence {
+				station.Tracks[t] = session.Sequence[t].Track
+			}
 
 			m.playlists.SetItem(i, station)
 		case playlist.LIKES:
@@ -417,25 +669,76 @@ func (m *Model) initialLoad() {
 
 			station.Tracks = likedTracks
 			m.playlists.SetItem(i, station)
+		case playlist.DISLIKES:
+			if m.client == nil {
+				continue
+			}
+
+			artists, err := m.client.DislikedArtists()
+			if err != nil {
+				log.Print(log.LVL_ERROR, "failed to obtain disliked artists: %s", err)
+				m.tracker.ShowError("disliked artists")
+			}
+			for _, artist := range artists {
+				m.dislikedArtistsMap[artist.Id] = true
+			}
+
+			dislikes, err := m.client.DislikedTracks()
+			if err != nil {
+				log.Print(log.LVL_ERROR, "failed to obtain disliked tracks: %s", err)
+				m.tracker.ShowError("disliked tracks")
+				continue
+			}
+			if len(dislikes) == 0 {
+				continue
+			}
+
+			dislikedTracksId := make([]string, len(dislikes))
+			for d, track := range dislikes {
+				m.dislikedTracksMap[track.Id] = true
+				dislikedTracksId[d] = track.Id
+			}
+
+			dislikedTracks, err := m.client.Tracks(dislikedTracksId)
+			if err != nil {
+				log.Print(log.LVL_ERROR, "failed to obtain disliked tracks full info: %s", err)
+				m.tracker.ShowError("disliked tracks info")
+				continue
+			}
+
+			station.Tracks = dislikedTracks
+			m.playlists.SetItem(i, station)
 		case playlist.LOCAL:
+			m.verifyCache()
 			station.Tracks, err = cache.ListTracks()
 			if err != nil {
+				// the music files are still listed without the cache
 				log.Print(log.LVL_ERROR, "failed to list cached tracks: %s", err)
 				m.tracker.ShowError("cache list")
-				continue
 			}
 			for i := range station.Tracks {
 				m.cachedTracksMap[station.Tracks[i].Id] = true
 			}
+			station.Tracks = append(station.Tracks, m.scanMusicFiles()...)
+			m.playlists.SetItem(i, station)
+		case playlist.HISTORY:
+			station.Tracks = m.historyTracks()
 			m.playlists.SetItem(i, station)
 		default:
 		}
 	}
 
+	var collective []api.Playlist
 	if m.client != nil {
 		playlists, err := m.client.ListPlaylists()
 		if err == nil {
 			for _, pl := range playlists {
+				if pl.Owner.Uid != m.client.UserId() {
+					// collective playlists of other users are placed with the followed ones
+					collective = append(collective, pl)
+					continue
+				}
+
 				playlistTracks, err := m.client.PlaylistTracks(pl.Kind, pl.Owner.Uid, false)
 				if err != nil {
 					log.Print(log.LVL_ERROR, "failed to obtain playlist [%s] tracks: %s", pl.Title, err)
@@ -447,6 +750,7 @@ func (m *Model) initialLoad() {
 					Name:     pl.Title,
 					Kind:     pl.Kind,
 					Revision: pl.Revision,
+					Seed:     playlistSeed(pl.Owner.Uid, pl.Kind),
 					Active:   true,
 					Subitem:  true,
 					Tracks:   playlistTracks,
@@ -457,8 +761,10 @@ func (m *Model) initialLoad() {
 			m.tracker.ShowError("playlists")
 		}
 	}
+	m.loadFollowedPlaylists(collective)
 
 	m.currentPlaylistIndex = -1
+	m.historyPos = -1
 	m.playlists.Select(0)
 	m.Send(LOADING_DONE)
 }
@@ -499,18 +805,21 @@ func (m *Model) mediaHandle() {
 
 		case handler.MSG_SET_SHUFFLE:
 			val, ok := msg.Arg.(bool)
-			if !ok || !val {
-				break
+			if ok {
+				m.Send(setShuffleMsg(val))
 			}
-			currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
-			if len(currentPlaylist.Tracks) == 0 {
+		case handler.MSG_SET_LOOPSTATUS:
+			loop, ok := msg.Arg.(handler.LoopStatus)
+			if !ok {
 				break
 			}
-			if currentPlaylist.Kind >= playlist.LIKES {
-				cmd := m.shufflePlaylist(currentPlaylist)
-				m.Send(func() tea.Cmd {
-					return cmd
-				})
+			switch loop {
+			case handler.LOOP_TRACK:
+				m.Send(setRepeatMsg(config.REPEAT_ONE))
+			case handler.LOOP_PLAYLIST:
+				m.Send(setRepeatMsg(config.REPEAT_ALL))
+			default:
+				m.Send(setRepeatMsg(config.REPEAT_NONE))
 			}
 		case handler.MSG_SET_VOLUME:
 			vol, ok := msg.Arg.(float64)
@@ -531,7 +840,20 @@ func (m *Model) mediaHandle() {
 			}
 			m.mediaHandler.SendAnswer(state)
 		case handler.MSG_GET_SHUFFLE:
-			m.mediaHandler.SendAnswer(false)
+			var shuffled bool
+			if m.currentPlaylistIndex >= 0 {
+				shuffled = m.playlists.Items()[m.currentPlaylistIndex].Shuffled
+			}
+			m.mediaHandler.SendAnswer(shuffled)
+		case handler.MSG_GET_LOOPSTATUS:
+			switch m.tracker.Repeat() {
+			case config.REPEAT_ONE:
+				m.mediaHandler.SendAnswer(handler.LOOP_TRACK)
+			case config.REPEAT_ALL:
+				m.mediaHandler.SendAnswer(handler.LOOP_PLAYLIST)
+			default:
+				m.mediaHandler.SendAnswer(handler.LOOP_NONE)
+			}
 		case handler.MSG_GET_METADATA:
 			if m.tracker.IsStoped() {
 				m.mediaHandler.SendAnswer(handler.TrackMetadata{})
@@ -0,0 +1,32 @@
+package mainpage
+
+import (
+	"slices"
+
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+)
+
+// scanMusicFiles lists the music files of the configured directories to be shown with the cached tracks.
+func (m *Model) scanMusicFiles() []api.Track {
+	if len(config.Current.MusicDirs) == 0 {
+		return nil
+	}
+
+	tracks, err := library.Scan(config.Current.MusicDirs)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to scan music directories: %s", err)
+		m.tracker.ShowError("music scan")
+	}
+
+	log.Print(log.LVL_INFO, "found %d music files", len(tracks))
+	return tracks
+}
+
+// streamedTracks removes the local music files from the tracks,
+// they are unknown to the server and can't be liked or added to playlists.
+func streamedTracks(tracks []api.Track) []api.Track {
+	return slices.DeleteFunc(tracks, func(t api.Track) bool { return library.IsLocal(&t) })
+}
@@ -3,6 +3,7 @@ package mainpage
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 
 	_ "image/jpeg"
@@ -11,99 +12,59 @@ import (
 	"github.com/bogem/id3v2/v2"
 	"github.com/dece2183/yamusic-tui/api"
 	"github.com/dece2183/yamusic-tui/cache"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/library"
 	"github.com/dece2183/yamusic-tui/log"
 	"github.com/dece2183/yamusic-tui/stream"
 	"github.com/dece2183/yamusic-tui/ui/components/playlist"
 	"github.com/dece2183/yamusic-tui/ui/components/tracker"
-	"github.com/dece2183/yamusic-tui/ui/components/tracklist"
 	"github.com/dece2183/yamusic-tui/ui/helpers"
 )
 
 const (
-	_TRACK_DOWNLOAD_TRIES     = 3
-	_TRACK_FINISHED_THRESHOLD = 0.8
+	_TRACK_DOWNLOAD_TRIES = 3
 )
 
-func (m *Model) feedbackOnTrack(batch string) *api.RotorFeedback {
-	currTrack := m.tracker.CurrentTrack()
-	if currTrack == nil {
-		return nil
-	}
-	var evType api.TrackEventType
-	if m.tracker.Progress() > _TRACK_FINISHED_THRESHOLD {
-		evType = api.EV_TRACK_FINISHED
-	} else {
-		evType = api.EV_TRACK_SKIPED
-	}
-	ev := api.NewTrackFeedbackEvent(evType, currTrack, m.tracker.Playtime().Seconds())
-	fb := api.NewFeedback(batch, ev)
-	log.Print(log.LVL_INFO, "feedback event sended: "+ev.Type+" track: "+currTrack.Title)
-	return fb
-}
-
-func (m *Model) rotateTracks(currentPlaylist *playlist.Item) {
-	if !currentPlaylist.Rotor {
-		return
-	}
-
-	suggestedTracks, err := m.client.RotorSessionTracks(currentPlaylist.SessionId, []*api.RotorFeedback{}, currentPlaylist.Tracks)
-	if err != nil {
-		log.Print(log.LVL_ERROR, "failed to obtain more rotor tracks: %s", err)
-		m.tracker.ShowError("next track obtain failure")
-		m.Send(tracker.STOP)
-		return
-	}
-
-	currentPlaylist.SessionBatch = suggestedTracks.BatchId
-	currentPlaylist.Tracks = append(currentPlaylist.Tracks, suggestedTracks.Sequence[0].Track)
-
-	if m.playlists.SelectedItem().IsSame(currentPlaylist) {
-		tackItems := m.tracklist.Items()
-		lastTrack := tackItems[len(tackItems)-1]
-		lastTrack.IsSuggestion = false
-		m.tracklist.SetItem(len(tackItems)-1, lastTrack)
-		m.tracklist.InsertItem(-1, tracklist.Item{
-			Track:        &currentPlaylist.Tracks[len(currentPlaylist.Tracks)-1],
-			Artists:      helpers.ArtistList(suggestedTracks.Sequence[0].Track.Artists),
-			IsSuggestion: true,
-		})
-	}
-}
-
 func (m *Model) prevTrack() {
-	if m.currentPlaylistIndex < 0 {
+	if m.currentPlaylistIndex < 0 || m.historyPos >= 0 {
+		// go back through the tracks played before
+		if !m.playPrevFromHistory() {
+			m.Send(tracker.STOP)
+		}
 		return
 	}
 
 	currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
 
-	if currentPlaylist.Rotor && m.tracker.IsPlaying() {
-		go m.client.RotorSessionFeedback(currentPlaylist.SessionId, m.feedbackOnTrack(currentPlaylist.SessionBatch))
+	if currentPlaylist.Rotor && !m.playingQueued && m.tracker.IsPlaying() {
+		m.rotorFeedback(currentPlaylist, m.trackFeedbackEvent())
 	}
 
-	if len(currentPlaylist.Tracks) == 0 || currentPlaylist.CurrentTrack == 0 {
-		m.Send(tracker.STOP)
-		return
+	if m.playingQueued {
+		// return to the playlist track interrupted by the queue
+		m.playingQueued = false
+		if currentPlaylist.CurrentTrack < len(currentPlaylist.Tracks) && m.isPlayable(&currentPlaylist.Tracks[currentPlaylist.CurrentTrack]) {
+			m.playTrack(&currentPlaylist.Tracks[currentPlaylist.CurrentTrack])
+			return
+		}
 	}
 
 	selectedPlaylist := m.playlists.SelectedItem()
 	shouldFollow := currentPlaylist.IsSame(selectedPlaylist) && m.tracklist.Index() == currentPlaylist.CurrentTrack
 
-	m.indicateCurrentTrackPlaying(false)
-
-	currentPlaylist.CurrentTrack--
-	for currentPlaylist.CurrentTrack > 0 && !currentPlaylist.Tracks[currentPlaylist.CurrentTrack].Available {
-		currentPlaylist.CurrentTrack--
-	}
+	index := m.prevPlayable(currentPlaylist)
 
 	m.playlists.SetItem(m.currentPlaylistIndex, currentPlaylist)
-	track := &currentPlaylist.Tracks[currentPlaylist.CurrentTrack]
-	if !track.Available {
-		m.Send(tracker.STOP)
+	if index < 0 {
+		// the beginning of the playlist, continue with the tracks played before it
+		if !m.playPrevFromHistory() {
+			m.Send(tracker.STOP)
+		}
 		return
 	}
 
-	m.playTrack(track)
+	m.indicateCurrentTrackPlaying(false)
+	m.playTrack(&currentPlaylist.Tracks[index])
 	if shouldFollow {
 		m.tracklist.Select(currentPlaylist.CurrentTrack)
 		currentPlaylist.SelectedTrack = currentPlaylist.CurrentTrack
@@ -112,14 +73,34 @@ func (m *Model) prevTrack() {
 }
 
 func (m *Model) nextTrack() {
+	if m.currentPlaylistIndex >= 0 {
+		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
+		if currentPlaylist.Rotor && m.playingFromPlaylist() && m.tracker.IsPlaying() {
+			m.rotorFeedback(currentPlaylist, m.trackFeedbackEvent())
+		}
+	}
+
+	if m.queue.Len() > 0 {
+		m.playQueued(0)
+		return
+	}
+
+	m.playingQueued = false
 	if m.currentPlaylistIndex < 0 {
+		m.historyPos = -1
+		m.Send(tracker.STOP)
 		return
 	}
 
 	currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
 
-	if currentPlaylist.Rotor && m.tracker.IsPlaying() {
-		go m.client.RotorSessionFeedback(currentPlaylist.SessionId, m.feedbackOnTrack(currentPlaylist.SessionBatch))
+	if m.historyPos >= 0 {
+		// return to the playlist track interrupted by the history
+		m.historyPos = -1
+		if currentPlaylist.CurrentTrack < len(currentPlaylist.Tracks) && m.isPlayable(&currentPlaylist.Tracks[currentPlaylist.CurrentTrack]) {
+			m.playTrack(&currentPlaylist.Tracks[currentPlaylist.CurrentTrack])
+			return
+		}
 	}
 
 	if len(currentPlaylist.Tracks) == 0 {
@@ -129,33 +110,47 @@ func (m *Model) nextTrack() {
 
 	m.indicateCurrentTrackPlaying(false)
 
-	if currentPlaylist.CurrentTrack+1 >= len(currentPlaylist.Tracks) {
-		currentPlaylist.CurrentTrack = 0
+	selectedPlaylist := m.playlists.SelectedItem()
+	shouldFollow := currentPlaylist.IsSame(selectedPlaylist) && m.tracklist.Index() == currentPlaylist.CurrentTrack
+
+	index := m.nextPlayable(currentPlaylist)
+	if index < 0 && m.rotateTracks(currentPlaylist) {
+		// the rotor lookahead ran out, continue with the tracks just received
+		index = m.nextPlayable(currentPlaylist)
+	}
+
+	if index < 0 && currentPlaylist.Rotor {
+		// the suggestions request failed, the position is kept and the playback
+		// continues with the tracks of the background retry
+		m.resumeRotor = true
+		m.refillRotor(currentPlaylist)
 		m.playlists.SetItem(m.currentPlaylistIndex, currentPlaylist)
-		m.Send(tracker.STOP)
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
 		return
 	}
 
-	selectedPlaylist := m.playlists.SelectedItem()
-	shouldFollow := currentPlaylist.IsSame(selectedPlaylist) && m.tracklist.Index() == currentPlaylist.CurrentTrack
-
-	currentPlaylist.CurrentTrack++
-	for currentPlaylist.CurrentTrack < len(currentPlaylist.Tracks)-1 && !currentPlaylist.Tracks[currentPlaylist.CurrentTrack].Available {
-		currentPlaylist.CurrentTrack++
+	if index < 0 {
+		if m.tracker.Repeat() != config.REPEAT_ALL {
+			currentPlaylist.Restart()
+			m.playlists.SetItem(m.currentPlaylistIndex, currentPlaylist)
+			m.Send(tracker.STOP)
+			return
+		}
+		// start over from the first track
+		index = currentPlaylist.Restart()
+		if index >= 0 && !m.isPlayable(&currentPlaylist.Tracks[index]) {
+			index = m.nextPlayable(currentPlaylist)
+		}
 	}
 
 	m.playlists.SetItem(m.currentPlaylistIndex, currentPlaylist)
-	track := &currentPlaylist.Tracks[currentPlaylist.CurrentTrack]
-	if !track.Available {
+	if index < 0 {
 		m.Send(tracker.STOP)
 		return
 	}
 
-	if currentPlaylist.CurrentTrack == len(currentPlaylist.Tracks)-1 {
-		m.rotateTracks(currentPlaylist)
-	}
-
-	m.playTrack(track)
+	m.refillRotor(currentPlaylist)
+	m.playTrack(&currentPlaylist.Tracks[index])
 	if shouldFollow {
 		m.tracklist.Select(currentPlaylist.CurrentTrack)
 		currentPlaylist.SelectedTrack = currentPlaylist.CurrentTrack
@@ -163,7 +158,53 @@ func (m *Model) nextTrack() {
 	}
 }
 
+// nextPlayable moves to the next playable track of the playlist and returns its index or -1.
+func (m *Model) nextPlayable(pl *playlist.Item) int {
+	index := pl.Next()
+	for index >= 0 && !m.isPlayable(&pl.Tracks[index]) {
+		index = pl.Next()
+	}
+	return index
+}
+
+// prevPlayable moves to the previous playable track of the playlist and returns its index or -1.
+func (m *Model) prevPlayable(pl *playlist.Item) int {
+	index := pl.Prev()
+	for index >= 0 && !m.isPlayable(&pl.Tracks[index]) {
+		index = pl.Prev()
+	}
+	return index
+}
+
+func (m *Model) trackEnded() {
+	if m.tracker.Repeat() == config.REPEAT_ONE {
+		track := *m.tracker.CurrentTrack()
+		m.playTrack(&track)
+		return
+	}
+
+	m.nextTrack()
+}
+
+func (m *Model) updatePlaybackOptions() {
+	var shuffled bool
+	if m.currentPlaylistIndex >= 0 {
+		shuffled = m.playlists.Items()[m.currentPlaylistIndex].Shuffled
+	}
+
+	m.tracker.SetShuffle(shuffled)
+	m.mediaHandler.OnOptions()
+}
+
+// playingFromPlaylist reports whether the playing track is the current track of the current playlist
+// rather than the one from the queue or the history.
+func (m *Model) playingFromPlaylist() bool {
+	return !m.playingQueued && m.historyPos < 0
+}
+
 func (m *Model) playTrack(track *api.Track) {
+	m.resumeRotor = false
+	m.recordPlayback()
 	m.tracker.Stop()
 
 	var (
@@ -183,8 +224,20 @@ func (m *Model) playTrack(track *api.Track) {
 
 	defer coverFile.Close()
 
+	// the cached cover is used without the network
+	if library.IsLocal(track) {
+		coverBytes, err = library.ReadCover(track)
+	} else {
+		coverBytes, err = cache.ReadCover(track.Id)
+	}
+	if err == nil {
+		coverType = http.DetectContentType(coverBytes)
+		coverFile.Write(coverBytes)
+		goto skipcover
+	}
+
 	coverStat, err = coverFile.Stat()
-	if err != nil || coverStat.Size() == 0 {
+	if (err != nil || coverStat.Size() == 0) && !library.IsLocal(track) {
 		coverType, err = api.DownloadTrackCover(coverFile, track, 200)
 		if err != nil {
 			log.Print(log.LVL_WARNIGN, "unable to download track [%s] cover: %s", track.Id, err)
@@ -202,45 +255,24 @@ skipcover:
 	var trackBuffer *stream.BufferedStream
 	var trackReader io.ReadCloser
 	var trackSize int64
-	var lyrics []api.LyricPair
-	if track.LyricsInfo.HasAvailableSyncLyrics {
-		lyrics, err = m.client.TrackLyricsRequest(track.Id)
+	m.playingLyrics, err = m.trackLyrics(track)
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "failed to obtain track [%s] lyrics: %s", track.Id, err)
+		m.tracker.ShowError("track lyrics")
+	}
+	lyrics := api.ParseLRCText(m.playingLyrics)
+	if library.IsLocal(track) {
+		trackReader, trackSize, err = library.Open(track)
 		if err != nil {
-			log.Print(log.LVL_WARNIGN, "failed to obtain track [%s] lyrics: %s", track.Id, err)
-			m.tracker.ShowError("track lyrics")
+			log.Print(log.LVL_ERROR, "failed to open music file [%s]: %s", library.FilePath(track), err)
+			m.tracker.ShowError("music file open")
+			return
 		}
-	}
-	trackReader, trackSize, err = cache.Read(track.Id)
-	if err == nil {
+		trackFromCache = true
+	} else if trackReader, trackSize, err = cache.Read(track.Id); err == nil {
 		trackFromCache = true
 	} else {
-		var trackInfos []api.TrackDownloadInfo
-		var bestTrackInfo api.TrackDownloadInfo
-
-		for i := 0; i < _TRACK_DOWNLOAD_TRIES; i++ {
-			trackInfos, err = m.client.TrackDownloadInfo(track.Id)
-			if err != nil {
-				log.Print(log.LVL_ERROR, "failed to obtain track [%s] info: %s", track.Id, err)
-				continue
-			}
-
-			var bestBitrate int
-			for _, t := range trackInfos {
-				if t.BbitrateInKbps > bestBitrate {
-					bestBitrate = t.BbitrateInKbps
-					bestTrackInfo = t
-				}
-			}
-
-			trackReader, trackSize, err = m.client.DownloadTrack(bestTrackInfo)
-			if err != nil {
-				log.Print(log.LVL_ERROR, "failed to download track [%s]: %s", track.Id, err)
-				continue
-			}
-
-			break
-		}
-
+		trackReader, trackSize, err = m.downloadTrack(track)
 		if err != nil {
 			m.tracker.ShowError("track download")
 			return
@@ -254,24 +286,7 @@ skipcover:
 		if trackFromCache {
 			tag.Reset(trackBuffer, id3v2.Options{Parse: true})
 		} else {
-			tag.SetDefaultEncoding(id3v2.EncodingUTF8)
-			tag.SetTitle(track.Title)
-			if len(track.Albums) != 0 {
-				tag.SetAlbum(track.Albums[0].Title)
-				tag.SetGenre(track.Albums[0].Genre)
-				tag.SetYear(fmt.Sprint(track.Albums[0].Year))
-			}
-			tag.SetArtist(helpers.ArtistList(track.Artists))
-			tag.AddAttachedPicture(id3v2.PictureFrame{
-				MimeType:    coverType,
-				PictureType: id3v2.PTFrontCover,
-				Encoding:    id3v2.EncodingUTF16BE,
-				Picture:     coverBytes,
-			})
-			tag.AddFrame("TLEN", id3v2.TextFrame{
-				Encoding: id3v2.EncodingUTF8,
-				Text:     fmt.Sprint(track.DurationMs),
-			})
+			setTrackTag(tag, track, coverType, coverBytes)
 		}
 		tag.WriteTo(metadataFile)
 		io.CopyN(metadataFile, trackBuffer, 32*1024)
@@ -281,22 +296,91 @@ skipcover:
 		log.Print(log.LVL_WARNIGN, "failed to create metadata file: %s", err)
 	}
 
-	if m.currentPlaylistIndex >= 0 {
+	if m.currentPlaylistIndex >= 0 && m.playingFromPlaylist() {
 		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
 		if currentPlaylist.Rotor {
-			ev := api.NewTrackFeedbackEvent(api.EV_TRACK_STARTED, track, 0)
-			go m.client.RotorSessionFeedback(currentPlaylist.SessionId, api.NewFeedback(currentPlaylist.SessionBatch, ev))
-			log.Print(log.LVL_INFO, "feedback event sended: "+ev.Type+" track: "+track.Title)
+			m.rotorFeedback(currentPlaylist, api.NewTrackFeedbackEvent(api.EV_TRACK_STARTED, track, 0))
 		}
 	}
 
 	m.tracker.StartTrack(track, trackBuffer, lyrics)
+	m.startPlayback(track, trackFromCache)
 	m.indicateCurrentTrackPlaying(true)
 	m.mediaHandler.OnPlayback()
+}
+
+// trackLyrics returns the synced lyrics of the track in the LRC format,
+// the cached lyrics are used without the network.
+// The lyrics of the local music files are read from the LRC files next to them.
+func (m *Model) trackLyrics(track *api.Track) (string, error) {
+	if library.IsLocal(track) {
+		lrc, _ := library.ReadLyrics(track)
+		return lrc, nil
+	}
+
+	lrc, err := cache.ReadLyrics(track.Id)
+	if err == nil {
+		return lrc, nil
+	}
+
+	if !track.LyricsInfo.HasAvailableSyncLyrics || m.client == nil {
+		return "", nil
+	}
+
+	return m.client.TrackLyricsLRC(track.Id)
+}
+
+// downloadTrack downloads the track in the best available bitrate.
+func (m *Model) downloadTrack(track *api.Track) (trackReader io.ReadCloser, trackSize int64, err error) {
+	var trackInfos []api.TrackDownloadInfo
+	var bestTrackInfo api.TrackDownloadInfo
 
-	if m.client != nil {
-		go m.client.PlayTrack(track, trackFromCache)
+	for i := 0; i < _TRACK_DOWNLOAD_TRIES; i++ {
+		trackInfos, err = m.client.TrackDownloadInfo(track.Id)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to obtain track [%s] info: %s", track.Id, err)
+			continue
+		}
+
+		var bestBitrate int
+		for _, t := range trackInfos {
+			if t.BbitrateInKbps > bestBitrate {
+				bestBitrate = t.BbitrateInKbps
+				bestTrackInfo = t
+			}
+		}
+
+		trackReader, trackSize, err = m.client.DownloadTrack(bestTrackInfo)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to download track [%s]: %s", track.Id, err)
+			continue
+		}
+
+		break
 	}
+
+	return
+}
+
+func setTrackTag(tag *id3v2.Tag, track *api.Track, coverType string, coverBytes []byte) {
+	tag.SetDefaultEncoding(id3v2.EncodingUTF8)
+	tag.SetTitle(track.Title)
+	if len(track.Albums) != 0 {
+		tag.SetAlbum(track.Albums[0].Title)
+		tag.SetGenre(track.Albums[0].Genre)
+		tag.SetYear(fmt.Sprint(track.Albums[0].Year))
+	}
+	tag.SetArtist(helpers.ArtistList(track.Artists))
+	tag.AddAttachedPicture(id3v2.PictureFrame{
+		MimeType:    coverType,
+		PictureType: id3v2.PTFrontCover,
+		Encoding:    id3v2.EncodingUTF16BE,
+		Picture:     coverBytes,
+	})
+	tag.AddFrame("TLEN", id3v2.TextFrame{
+		Encoding: id3v2.EncodingUTF8,
+		Text:     fmt.Sprint(track.DurationMs),
+	})
 }
 
 func (m *Model) playSelectedPlaylist(trackIndex int) {
@@ -310,7 +394,7 @@ func (m *Model) playSelectedPlaylist(trackIndex int) {
 
 	if m.currentPlaylistIndex >= 0 {
 		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
-		if currentPlaylist.IsSame(selectedPlaylist) && selectedPlaylist.CurrentTrack == trackIndex && m.tracker.CurrentTrack().Id == trackToPlay.Id {
+		if m.playingFromPlaylist() && currentPlaylist.IsSame(selectedPlaylist) && selectedPlaylist.CurrentTrack == trackIndex && m.tracker.CurrentTrack().Id == trackToPlay.Id {
 			if m.tracker.IsPlaying() {
 				m.tracker.Pause()
 				return
@@ -320,32 +404,29 @@ func (m *Model) playSelectedPlaylist(trackIndex int) {
 			}
 		}
 		if currentPlaylist.Rotor {
-			if m.tracker.IsPlaying() {
-				go m.client.RotorSessionFeedback(currentPlaylist.SessionId, m.feedbackOnTrack(currentPlaylist.SessionBatch))
+			if m.playingFromPlaylist() && m.tracker.IsPlaying() {
+				m.rotorFeedback(currentPlaylist, m.trackFeedbackEvent())
 			}
 			if !currentPlaylist.IsSame(selectedPlaylist) {
-				ev := api.NewRadioFeedbackEvent(api.EV_RADIO_FINISHED)
-				go m.client.RotorSessionFeedback(currentPlaylist.SessionId, api.NewFeedback(currentPlaylist.SessionBatch, ev))
-				log.Print(log.LVL_INFO, "feedback event sended: "+ev.Type)
+				m.finishRotor(currentPlaylist)
 			}
 		}
 	}
 
 	m.indicateCurrentTrackPlaying(false)
+	m.playingQueued = false
+	m.historyPos = -1
 
 	if selectedPlaylist.Rotor {
-		if trackIndex == len(selectedPlaylist.Tracks)-1 {
-			m.rotateTracks(selectedPlaylist)
-		}
 		if m.currentPlaylistIndex != m.playlists.Index() {
-			ev := api.NewRadioFeedbackEvent(api.EV_RADIO_STARTED)
-			go m.client.RotorSessionFeedback(selectedPlaylist.SessionId, api.NewFeedback("", ev))
-			log.Print(log.LVL_INFO, "feedback event sended: "+ev.Type)
+			m.rotorFeedback(selectedPlaylist, api.NewRadioFeedbackEvent(api.EV_RADIO_STARTED))
 		}
 	}
 
-	selectedPlaylist.CurrentTrack = trackIndex
+	selectedPlaylist.Select(trackIndex)
+	m.refillRotor(selectedPlaylist)
 	m.currentPlaylistIndex = m.playlists.Index()
 	m.playlists.SetItem(m.currentPlaylistIndex, selectedPlaylist)
+	m.updatePlaybackOptions()
 	m.playTrack(trackToPlay)
 }
@@ -1,17 +1,18 @@
 package mainpage
 
 import (
-	"math/rand"
 	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
 	"github.com/dece2183/yamusic-tui/log"
 	"github.com/dece2183/yamusic-tui/ui/components/input"
 	"github.com/dece2183/yamusic-tui/ui/components/playlist"
 	"github.com/dece2183/yamusic-tui/ui/components/search"
 	"github.com/dece2183/yamusic-tui/ui/components/tracklist"
+	"github.com/dece2183/yamusic-tui/ui/style"
 )
 
 func (m *Model) addPlaylistControl(msg search.Control) tea.Cmd {
@@ -26,6 +27,12 @@ func (m *Model) addPlaylistControl(msg search.Control) tea.Cmd {
 			return nil
 		}
 
+		tracks := streamedTracks(m.selectedTracks())
+		if len(tracks) == 0 {
+			m.tracker.ShowError("local files can't be added to playlists")
+			return nil
+		}
+
 		playlists := m.playlists.Items()
 		inputVal, ok := m.searchDialog.SuggestionValue()
 		if !ok {
@@ -46,6 +53,10 @@ func (m *Model) addPlaylistControl(msg search.Control) tea.Cmd {
 			}
 		}
 
+		if foundPlaylistIndex < 0 {
+			foundPlaylistIndex = m.sectionEnd(_PLAYLISTS_HEADER)
+		}
+
 		if foundPlaylist == nil {
 			pl, err := m.client.CreatePlaylist(inputVal, true)
 			if err != nil {
@@ -58,6 +69,7 @@ func (m *Model) addPlaylistControl(msg search.Control) tea.Cmd {
 				Name:     pl.Title,
 				Kind:     pl.Kind,
 				Revision: pl.Revision,
+				Seed:     playlistSeed(m.client.UserId(), pl.Kind),
 				Active:   true,
 				Subitem:  true,
 			}
@@ -75,16 +87,22 @@ func (m *Model) addPlaylistControl(msg search.Control) tea.Cmd {
 			return nil
 		}
 
-		selectedTrack := &selectedPlaylist.Tracks[m.tracklist.Index()]
-		pl, err := m.client.AddToPlaylist(foundPlaylist.Kind, foundPlaylist.Revision, len(foundPlaylist.Tracks), selectedTrack.Id)
+		diffTracks := make([]api.PlaylistDiffTrack, len(tracks))
+		for i := range tracks {
+			diffTracks[i] = api.NewPlaylistDiffTrack(&tracks[i])
+		}
+
+		diff := api.PlaylistDiff{}.Insert(len(foundPlaylist.Tracks), diffTracks...)
+		pl, err := m.client.ChangePlaylist(foundPlaylist.Kind, foundPlaylist.Revision, diff)
 		if err != nil {
-			log.Print(log.LVL_ERROR, "failed to add track [%s] to playlist [%s]: %s", selectedTrack.Id, foundPlaylist.Name, err)
+			log.Print(log.LVL_ERROR, "failed to add %d tracks to playlist [%s]: %s", len(tracks), foundPlaylist.Name, err)
 			m.tracker.ShowError("playlist add")
 			return nil
 		}
 
 		foundPlaylist.Revision = pl.Revision
-		foundPlaylist.Tracks = append(foundPlaylist.Tracks, *selectedTrack)
+		foundPlaylist.Tracks = append(foundPlaylist.Tracks, tracks...)
+		m.syncOffline(foundPlaylist)
 		cmd = m.playlists.SetItem(foundPlaylistIndex, foundPlaylist)
 
 		m.isAddPlaylistActive = false
@@ -133,116 +151,168 @@ func (m *Model) renamePlaylistControl(msg input.Control) tea.Cmd {
 	return cmd
 }
 
-func (m *Model) removeFromPlaylist(pl *playlist.Item, index int) tea.Cmd {
-	if index >= len(pl.Tracks) {
+// showEditPlaylistDialog offers to change the selected playlist description and visibility
+// and shows its cover link.
+func (m *Model) showEditPlaylistDialog() {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if selectedPlaylist.Kind < playlist.USER || m.client == nil {
+		return
+	}
+
+	pl, err := m.client.Playlist(selectedPlaylist.Kind)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain playlist [%s]: %s", selectedPlaylist.Name, err)
+		m.tracker.ShowError("playlist info")
+		return
+	}
+
+	m.editedPlaylist = pl
+	m.inputDialog.Title = "Edit playlist " + selectedPlaylist.Name + " description"
+	m.inputDialog.Action = "apply"
+	m.inputDialog.SetValue(pl.Description)
+	m.updateEditPlaylistDialog()
+	m.isEditPlaylistActive = true
+}
+
+func (m *Model) updateEditPlaylistDialog() {
+	public := m.editedPlaylist.Visibility == "public"
+
+	info := "Visibility: " + m.editedPlaylist.Visibility
+	if link := api.PlaylistCoverLink(&m.editedPlaylist, 400); len(link) > 0 {
+		info += "\nCover: " + link
+	} else {
+		info += "\nCover: none"
+	}
+
+	m.inputDialog.Info = info
+	if public {
+		m.inputDialog.ToggleAction = "make private"
+	} else {
+		m.inputDialog.ToggleAction = "make public"
+	}
+}
+
+func (m *Model) editPlaylistControl(msg input.Control) tea.Cmd {
+	switch msg {
+	case input.TOGGLE:
+		if m.editedPlaylist.Visibility == "public" {
+			m.editedPlaylist.Visibility = "private"
+		} else {
+			m.editedPlaylist.Visibility = "public"
+		}
+		m.updateEditPlaylistDialog()
+		return nil
+	case input.CANCEL:
+		m.isEditPlaylistActive = false
 		return nil
 	}
 
-	switch pl.Kind {
-	case playlist.NONE, playlist.MYWAVE:
+	m.isEditPlaylistActive = false
+
+	selectedPlaylist := m.playlists.SelectedItem()
+	if selectedPlaylist.Kind != m.editedPlaylist.Kind {
 		return nil
-	case playlist.LIKES:
-		selectedTrack := pl.Tracks[index]
-		return m.likeTrack(&selectedTrack, pl)
-	case playlist.LOCAL:
-		selectedTrack := pl.Tracks[index]
-		return m.removeCache(&selectedTrack)
-	default:
-		var cmd tea.Cmd
+	}
 
-		if len(pl.Tracks) < 2 {
-			err := m.client.RemovePlaylist(pl.Kind)
-			if err != nil {
-				log.Print(log.LVL_ERROR, "failed to remove playlist [%s]: %s", pl.Name, err)
-				m.tracker.ShowError("playlist remove")
-				return nil
-			}
-			if m.currentPlaylistIndex >= m.playlists.Index() && m.tracker.IsPlaying() {
-				m.currentPlaylistIndex -= 1
-			}
-			m.playlists.RemoveItem(m.playlists.Index())
-			if len(m.playlists.Items()) <= m.playlists.Index() {
-				m.playlists.Select(0)
-			}
-			m.displayPlaylist(m.playlists.SelectedItem())
+	serverpl, err := m.client.Playlist(selectedPlaylist.Kind)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain playlist [%s]: %s", selectedPlaylist.Name, err)
+		m.tracker.ShowError("playlist info")
+		return nil
+	}
+
+	revision := serverpl.Revision
+	newDescription := strings.TrimSpace(m.inputDialog.Value())
+	if newDescription != serverpl.Description {
+		pl, err := m.client.SetPlaylistDescription(selectedPlaylist.Kind, newDescription)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to change playlist [%s] description: %s", selectedPlaylist.Name, err)
+			m.tracker.ShowError("playlist description")
 			return nil
 		}
+		revision = pl.Revision
+	}
 
-		newpl, err := m.client.RemoveFromPlaylist(pl.Kind, pl.Revision, index)
+	if m.editedPlaylist.Visibility != serverpl.Visibility {
+		pl, err := m.client.SetPlaylistVisibility(selectedPlaylist.Kind, m.editedPlaylist.Visibility == "public")
 		if err != nil {
-			log.Print(log.LVL_ERROR, "failed to remove track [%s] from playlist [%s]: %s", pl.Tracks[index].Id, pl.Name, err)
-			m.tracker.ShowError("playlist remove track")
+			log.Print(log.LVL_ERROR, "failed to change playlist [%s] visibility: %s", selectedPlaylist.Name, err)
+			m.tracker.ShowError("playlist visibility")
 			return nil
 		}
+		revision = pl.Revision
+	}
 
-		pl.Revision = newpl.Revision
-		pl.Tracks = slices.Delete(pl.Tracks, index, index+1)
-		if index >= len(pl.Tracks) {
-			pl.SelectedTrack = len(pl.Tracks) - 1
-		} else {
-			pl.SelectedTrack = index
-		}
-		deleteCurrentTrack := index == pl.CurrentTrack
-		if deleteCurrentTrack {
-			pl.CurrentTrack = len(pl.Tracks)
-		} else if pl.CurrentTrack > index {
-			pl.CurrentTrack--
-		}
-		cmd = m.playlists.SetItem(m.playlists.Index(), pl)
-		m.displayPlaylist(pl)
+	if selectedPlaylist.Revision == serverpl.Revision {
+		selectedPlaylist.Revision = revision
+	}
+	return m.playlists.SetItem(m.playlists.Index(), selectedPlaylist)
+}
 
-		if m.currentPlaylistIndex >= 0 {
-			currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
-			if pl.IsSame(currentPlaylist) && m.tracker.IsPlaying() {
-				m.indicateCurrentTrackPlaying(!deleteCurrentTrack)
-			}
-		}
+// removeFromPlaylist removes the tracks at the ascending indexes from the playlist.
+func (m *Model) removeFromPlaylist(pl *playlist.Item, indexes []int) tea.Cmd {
+	indexes = slices.DeleteFunc(indexes, func(i int) bool { return i >= len(pl.Tracks) })
+	if len(indexes) == 0 {
+		return nil
+	}
 
-		return cmd
+	if pl.Kind >= playlist.USER {
+		return m.removeFromUserPlaylist(pl, indexes)
 	}
+
+	// the tracks are removed one by one from the end to keep the indexes valid
+	cmds := make([]tea.Cmd, 0, len(indexes))
+	for i := len(indexes) - 1; i >= 0; i-- {
+		cmds = append(cmds, m.removeTrack(pl, indexes[i]))
+	}
+	return tea.Batch(cmds...)
 }
 
-func (m *Model) shufflePlaylist(pl *playlist.Item) tea.Cmd {
-	var cmds []tea.Cmd
-	if pl.Kind == playlist.NONE || pl.Kind == playlist.MYWAVE || len(pl.Tracks) == 0 {
-		return nil
+func (m *Model) removeTrack(pl *playlist.Item, index int) tea.Cmd {
+	if pl.Rotor {
+		return m.skipSuggestion(pl, index)
 	}
 
-	currentTrackIndex := pl.CurrentTrack
-	selectedTrackIndex := pl.SelectedTrack
-	currentTrack := pl.Tracks[currentTrackIndex]
-	selectedTrack := pl.Tracks[selectedTrackIndex]
+	selectedTrack := pl.Tracks[index]
+	switch pl.Kind {
+	case playlist.LIKES:
+		return m.likeTrack(&selectedTrack, pl)
+	case playlist.DISLIKES:
+		return m.dislikeTrack(&selectedTrack, pl)
+	case playlist.LOCAL:
+		return m.removeCache(&selectedTrack)
+	case playlist.QUEUE:
+		return m.removeFromQueue(index)
+	default:
+		return nil
+	}
+}
 
-	tracks := make([]api.Track, len(pl.Tracks))
-	trackList := make([]tracklist.Item, len(pl.Tracks))
-	perm := rand.Perm(len(tracks))
+func (m *Model) shufflePlaylist(pl *playlist.Item, shuffle bool) tea.Cmd {
+	if pl.Kind == playlist.NONE || pl.Rotor || pl.Kind == playlist.QUEUE || len(pl.Tracks) == 0 {
+		return nil
+	}
 
-	for i, v := range perm {
-		tracks[v] = pl.Tracks[i]
-		trackList[v] = tracklist.NewItem(&tracks[v])
-		if currentTrack.Id == tracks[v].Id {
-			currentTrackIndex = v
-		}
-		if selectedTrackIndex > 0 && selectedTrack.Id == tracks[v].Id {
-			selectedTrackIndex = v
-		}
+	if shuffle {
+		pl.Shuffle(config.Current.SmartShuffle)
+	} else {
+		pl.Unshuffle()
 	}
 
-	pl.Tracks = tracks
-	pl.SelectedTrack = selectedTrackIndex
-	pl.CurrentTrack = currentTrackIndex
-	cmds = append(cmds, m.playlists.SetItem(m.playlists.Index(), pl))
-	cmds = append(cmds, m.tracklist.SetItems(trackList))
-	m.tracklist.Select(selectedTrackIndex)
+	index := m.playlists.IndexOf(pl)
+	if index < 0 {
+		return nil
+	}
 
-	if m.currentPlaylistIndex >= 0 {
-		currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
-		if pl.IsSame(currentPlaylist) && m.tracker.IsPlaying() {
-			m.indicateCurrentTrackPlaying(true)
-		}
+	cmd := m.playlists.SetItem(index, pl)
+	if index == m.playlists.Index() {
+		m.displayPlaylist(pl)
+	}
+	if index == m.currentPlaylistIndex {
+		m.updatePlaybackOptions()
 	}
 
-	return tea.Batch(cmds...)
+	return cmd
 }
 
 func (m *Model) displayPlaylist(pl *playlist.Item) {
@@ -250,8 +320,10 @@ func (m *Model) displayPlaylist(pl *playlist.Item) {
 	for i := range pl.Tracks {
 		trackList[i] = tracklist.NewItem(&pl.Tracks[i])
 	}
-	if pl.Rotor && len(trackList) > 0 {
-		trackList[len(trackList)-1].IsSuggestion = true
+	if pl.Rotor {
+		for i := pl.CurrentTrack + 1; i < len(trackList); i++ {
+			trackList[i].IsSuggestion = true
+		}
 	}
 
 	m.tracklist.SetItems(trackList)
@@ -260,22 +332,54 @@ func (m *Model) displayPlaylist(pl *playlist.Item) {
 	switch pl.Kind {
 	case playlist.MYWAVE:
 		m.tracklist.Title = "My wave"
+	case playlist.WAVE:
+		m.tracklist.Title = "Wave from " + pl.Name
 	case playlist.LIKES:
 		m.tracklist.Title = "Liked tracks"
+	case playlist.DISLIKES:
+		m.tracklist.Title = m.dislikesTitle()
 	case playlist.LOCAL:
 		m.tracklist.Title = "Cached tracks"
+		if len(config.Current.MusicDirs) > 0 {
+			m.tracklist.Title = "Local tracks"
+		}
+	case playlist.QUEUE:
+		m.tracklist.Title = "Play queue"
+	case playlist.HISTORY:
+		m.tracklist.Title = "Recently played"
 	default:
 		m.tracklist.Title = "Tracks from " + pl.Name
 	}
+	if pl.Shuffled {
+		m.tracklist.Title += " " + style.IconShuffle
+	}
 }
 
 func (m *Model) indicateCurrentTrackPlaying(playing bool) {
 	if m.currentPlaylistIndex < 0 {
 		return
 	}
+	if !m.playingFromPlaylist() {
+		// the playing track is not the one from the current playlist
+		playing = false
+	}
 	currentPlaylist := m.playlists.Items()[m.currentPlaylistIndex]
-	if currentPlaylist.IsSame(m.playlists.SelectedItem()) && currentPlaylist.CurrentTrack < len(m.tracklist.Items()) {
-		track := m.tracklist.Items()[currentPlaylist.CurrentTrack]
+	if !currentPlaylist.IsSame(m.playlists.SelectedItem()) {
+		return
+	}
+
+	items := m.tracklist.Items()
+	if currentPlaylist.Rotor {
+		// the suggestions are the tracks after the current one
+		for i := range items {
+			if isSuggestion := i > currentPlaylist.CurrentTrack; items[i].IsSuggestion != isSuggestion {
+				items[i].IsSuggestion = isSuggestion
+				m.tracklist.SetItem(i, items[i])
+			}
+		}
+	}
+	if currentPlaylist.CurrentTrack < len(items) {
+		track := items[currentPlaylist.CurrentTrack]
 		track.IsPlaying = playing
 		m.tracklist.SetItem(currentPlaylist.CurrentTrack, track)
 	}
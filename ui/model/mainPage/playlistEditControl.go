@@ -0,0 +1,231 @@
+package mainpage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+// selectedIndexes returns the marked tracks or the track under the cursor if nothing is marked.
+func (m *Model) selectedIndexes() []int {
+	indexes := m.tracklist.MarkedIndexes()
+	if len(indexes) == 0 && len(m.tracklist.Items()) > 0 {
+		indexes = []int{m.tracklist.Index()}
+	}
+	return indexes
+}
+
+// selectedTracks returns copies of the selected tracks in the tracklist order.
+func (m *Model) selectedTracks() []api.Track {
+	items := m.tracklist.Items()
+	indexes := m.selectedIndexes()
+	tracks := make([]api.Track, len(indexes))
+	for i, index := range indexes {
+		tracks[i] = *items[index].Track
+	}
+	return tracks
+}
+
+// removeFromUserPlaylist removes the tracks at the ascending indexes with a single change.
+// The playlist itself is removed only with its last single track,
+// removing all the marked tracks leaves the playlist empty.
+func (m *Model) removeFromUserPlaylist(pl *playlist.Item, indexes []int) tea.Cmd {
+	if len(pl.Tracks) == 1 && len(indexes) == 1 {
+		return m.removeUserPlaylist(pl)
+	}
+
+	// delete from the end, so the preceding indexes stay valid,
+	// the adjacent tracks are deleted at once
+	var diff api.PlaylistDiff
+	for end := len(indexes); end > 0; {
+		start := end - 1
+		for start > 0 && indexes[start-1] == indexes[start]-1 {
+			start--
+		}
+		diff = diff.Delete(indexes[start], indexes[end-1]+1)
+		end = start
+	}
+
+	keep := make([]int, 0, len(pl.Tracks)-len(indexes))
+	for i := range pl.Tracks {
+		if _, found := slices.BinarySearch(indexes, i); !found {
+			keep = append(keep, i)
+		}
+	}
+
+	cmd, _ := m.changePlaylist(pl, diff, keep)
+	return cmd
+}
+
+func (m *Model) removeUserPlaylist(pl *playlist.Item) tea.Cmd {
+	err := m.client.RemovePlaylist(pl.Kind)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to remove playlist [%s]: %s", pl.Name, err)
+		m.tracker.ShowError("playlist remove")
+		return nil
+	}
+
+	index := m.playlists.IndexOf(pl)
+	if index < 0 {
+		return nil
+	}
+	if m.currentPlaylistIndex == index {
+		m.currentPlaylistIndex = -1
+	} else if m.currentPlaylistIndex > index {
+		m.currentPlaylistIndex--
+	}
+	m.playlists.RemoveItem(index)
+	if len(m.playlists.Items()) <= m.playlists.Index() {
+		m.playlists.Select(0)
+	}
+	m.displayPlaylist(m.playlists.SelectedItem())
+	return nil
+}
+
+// moveInPlaylist moves the tracks at the ascending indexes by one position up or down with a single change.
+// The tracks that reach the playlist edge stay in place.
+func (m *Model) moveInPlaylist(pl *playlist.Item, indexes []int, offset int) tea.Cmd {
+	order := make([]int, len(pl.Tracks))
+	for i := range order {
+		order[i] = i
+	}
+
+	// move the tracks starting from the side they're moving to,
+	// so a track is never moved over the other moving one
+	moving := slices.Clone(indexes)
+	if offset > 0 {
+		slices.Reverse(moving)
+	}
+
+	var diff api.PlaylistDiff
+	for _, index := range moving {
+		pos := slices.Index(order, index)
+		target := pos + offset
+		if pos < 0 || target < 0 || target >= len(order) {
+			continue
+		}
+		if _, marked := slices.BinarySearch(indexes, order[target]); marked {
+			continue
+		}
+
+		diff = diff.Delete(pos, pos+1)
+		diff = diff.Insert(target, api.NewPlaylistDiffTrack(&pl.Tracks[index]))
+		order[pos], order[target] = order[target], order[pos]
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+
+	wasMarked := len(m.tracklist.MarkedIndexes()) > 0
+	cmd, ok := m.changePlaylist(pl, diff, order)
+
+	if ok && wasMarked {
+		// keep the moved tracks marked to move them further
+		marked := make([]int, 0, len(indexes))
+		for i, index := range order {
+			if _, found := slices.BinarySearch(indexes, index); found {
+				marked = append(marked, i)
+			}
+		}
+		if m.playlists.SelectedItem() == pl {
+			m.tracklist.SetMarked(marked)
+		}
+	}
+
+	return cmd
+}
+
+// changePlaylist sends the diff as a single change and rearranges the local tracks by the indexes.
+// If the playlist was changed elsewhere, the diff is sent again with the new revision while the tracks are the same,
+// otherwise the playlist is reloaded and the change is discarded.
+// It reports whether the change was applied.
+func (m *Model) changePlaylist(pl *playlist.Item, diff api.PlaylistDiff, indexes []int) (tea.Cmd, bool) {
+	newpl, err := m.client.ChangePlaylist(pl.Kind, pl.Revision, diff)
+	if errors.Is(err, api.ErrWrongRevision) {
+		log.Print(log.LVL_WARNIGN, "playlist [%s] revision %d is outdated", pl.Name, pl.Revision)
+
+		var serverpl api.Playlist
+		serverpl, err = m.client.Playlist(pl.Kind)
+		if err == nil {
+			if !sameTracks(&serverpl, pl.Tracks) {
+				return m.reloadPlaylist(pl, &serverpl), false
+			}
+			newpl, err = m.client.ChangePlaylist(pl.Kind, serverpl.Revision, diff)
+		}
+	}
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to change playlist [%s]: %s", pl.Name, err)
+		m.tracker.ShowError("playlist change")
+		return nil, false
+	}
+
+	pl.Revision = newpl.Revision
+	pl.Rearrange(indexes)
+	return m.updatePlaylist(pl), true
+}
+
+// reloadPlaylist replaces the tracks with the ones changed elsewhere.
+func (m *Model) reloadPlaylist(pl *playlist.Item, serverpl *api.Playlist) tea.Cmd {
+	tracks, err := m.client.PlaylistTracks(pl.Kind, serverpl.Owner.Uid, false)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to reload playlist [%s] tracks: %s", pl.Name, err)
+		m.tracker.ShowError("playlist tracks")
+		return nil
+	}
+
+	log.Print(log.LVL_WARNIGN, "playlist [%s] was changed elsewhere, reloaded revision %d", pl.Name, serverpl.Revision)
+	m.tracker.ShowError("playlist was changed elsewhere, try again")
+
+	var currentId string
+	if index := m.playlists.IndexOf(pl); index >= 0 && index == m.currentPlaylistIndex && pl.CurrentTrack < len(pl.Tracks) {
+		currentId = pl.Tracks[pl.CurrentTrack].Id
+	}
+
+	pl.Revision = serverpl.Revision
+	pl.Tracks = tracks
+	pl.CurrentTrack = slices.IndexFunc(tracks, func(t api.Track) bool { return t.Id == currentId })
+	if pl.CurrentTrack < 0 {
+		pl.CurrentTrack = len(tracks)
+	}
+	pl.SelectedTrack = min(pl.SelectedTrack, max(len(tracks)-1, 0))
+	if pl.Shuffled {
+		pl.Shuffle(config.Current.SmartShuffle)
+	}
+
+	return m.updatePlaylist(pl)
+}
+
+// updatePlaylist stores the changed playlist and displays it if selected.
+func (m *Model) updatePlaylist(pl *playlist.Item) tea.Cmd {
+	index := m.playlists.IndexOf(pl)
+	if index < 0 {
+		return nil
+	}
+
+	m.syncOffline(pl)
+	cmd := m.playlists.SetItem(index, pl)
+	if index == m.playlists.Index() {
+		m.displayPlaylist(pl)
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+	return cmd
+}
+
+func sameTracks(serverpl *api.Playlist, tracks []api.Track) bool {
+	if serverpl.TrackCount != len(tracks) || len(serverpl.Tracks) != len(tracks) {
+		return false
+	}
+	for i := range tracks {
+		if fmt.Sprint(serverpl.Tracks[i].Id) != tracks[i].Id {
+			return false
+		}
+	}
+	return true
+}
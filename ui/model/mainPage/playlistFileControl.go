@@ -0,0 +1,346 @@
+package mainpage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/playlistfile"
+	"github.com/dece2183/yamusic-tui/ui/components/input"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+	"github.com/dece2183/yamusic-tui/ui/components/search"
+	"github.com/dece2183/yamusic-tui/ui/helpers"
+)
+
+// review dialog suggestion to import the playlist without the entry
+const _SKIP_MATCH = "skip"
+
+type importEntry struct {
+	playlistfile.Entry
+	track      *api.Track
+	candidates []api.Track
+}
+
+// playlistImport is the playlist file being imported,
+// the entries without the single matching track are reviewed one by one.
+type playlistImport struct {
+	name    string
+	entries []importEntry
+	review  int
+}
+
+// importProgressMsg reports the number of the playlist file entries resolved in the background.
+type importProgressMsg struct {
+	done  int
+	total int
+}
+
+// importMsg is sent when all entries of the playlist file are resolved.
+type importMsg *playlistImport
+
+func (m *Model) showExportPlaylistDialog() {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if len(selectedPlaylist.Tracks) == 0 {
+		return
+	}
+
+	dir, err := library.Dir()
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "unable to get the library directory: %s", err)
+	}
+	fileName := strings.NewReplacer("/", "_", "\\", "_").Replace(selectedPlaylist.Name)
+
+	m.inputDialog.Title = "Export playlist " + selectedPlaylist.Name + " to"
+	m.inputDialog.Action = "export"
+	m.inputDialog.Info = "the format is chosen by the extension: .m3u8, .xspf or .json"
+	m.inputDialog.ToggleAction = ""
+	m.inputDialog.SetValue(filepath.Join(dir, fileName+".m3u8"))
+	m.isExportPlaylistActive = true
+}
+
+func (m *Model) exportPlaylistControl(msg input.Control) tea.Cmd {
+	m.isExportPlaylistActive = false
+	if msg != input.APPLY {
+		return nil
+	}
+
+	path := expandHome(strings.TrimSpace(m.inputDialog.Value()))
+	if len(path) == 0 {
+		return nil
+	}
+
+	selectedPlaylist := m.playlists.SelectedItem()
+	err := playlistfile.Save(path, selectedPlaylist.Name, selectedPlaylist.Tracks)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to export playlist [%s]: %s", selectedPlaylist.Name, err)
+		m.tracker.ShowError("playlist export")
+		return nil
+	}
+
+	log.Print(log.LVL_INFO, "exported playlist [%s] to '%s'", selectedPlaylist.Name, path)
+	return nil
+}
+
+func (m *Model) showImportPlaylistDialog() {
+	if m.client == nil || m.importing != nil {
+		return
+	}
+
+	dir, err := library.Dir()
+	if err != nil {
+		log.Print(log.LVL_WARNIGN, "unable to get the library directory: %s", err)
+	}
+
+	m.inputDialog.Title = "Import playlist from"
+	m.inputDialog.Action = "import"
+	m.inputDialog.Info = ".m3u8, .xspf or .json file"
+	m.inputDialog.ToggleAction = ""
+	m.inputDialog.SetValue(dir + string(filepath.Separator))
+	m.isImportPlaylistActive = true
+}
+
+func (m *Model) importPlaylistControl(msg input.Control) tea.Cmd {
+	m.isImportPlaylistActive = false
+	if msg != input.APPLY {
+		return nil
+	}
+
+	path := expandHome(strings.TrimSpace(m.inputDialog.Value()))
+	if len(path) == 0 {
+		return nil
+	}
+
+	name, entries, err := playlistfile.Load(path)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to read playlist file '%s': %s", path, err)
+		m.tracker.ShowError("playlist import")
+		return nil
+	}
+	if len(entries) == 0 {
+		m.tracker.ShowError("playlist file is empty")
+		return nil
+	}
+
+	imp := &playlistImport{
+		name:    name,
+		entries: make([]importEntry, len(entries)),
+	}
+	for i := range entries {
+		imp.entries[i].Entry = entries[i]
+	}
+
+	m.importing = imp
+	m.tracker.ShowProgress("importing", 0, len(entries))
+	go m.resolveImport(imp)
+	return nil
+}
+
+// resolveImport finds the tracks of the playlist file entries,
+// the entries with the track ids are requested directly, the rest are searched.
+func (m *Model) resolveImport(imp *playlistImport) {
+	var ids []string
+	for i := range imp.entries {
+		if len(imp.entries[i].Id) > 0 {
+			ids = append(ids, imp.entries[i].Id)
+		}
+	}
+
+	if len(ids) > 0 {
+		tracks, err := m.client.Tracks(ids)
+		if err != nil {
+			log.Print(log.LVL_WARNIGN, "failed to obtain imported tracks info: %s", err)
+		}
+
+		found := make(map[string]*api.Track, len(tracks))
+		for i := range tracks {
+			found[tracks[i].Id] = &tracks[i]
+		}
+		for i := range imp.entries {
+			if track, ok := found[imp.entries[i].Id]; ok {
+				imp.entries[i].track = track
+			}
+		}
+	}
+
+	for i := range imp.entries {
+		entry := &imp.entries[i]
+		if entry.track == nil && len(entry.Title) > 0 {
+			result, err := m.client.Search(entry.Query(), api.SEARCH_TRACK)
+			if err != nil {
+				log.Print(log.LVL_WARNIGN, "failed to search imported track [%s]: %s", entry.String(), err)
+			} else {
+				entry.track, entry.candidates = playlistfile.Match(&entry.Entry, result.Tracks.Results)
+			}
+		}
+		m.program.Send(importProgressMsg{done: i + 1, total: len(imp.entries)})
+	}
+
+	m.program.Send(importMsg(imp))
+}
+
+// reviewImport asks to choose the track for the next entry without the single match.
+// The playlist is created when all entries are reviewed.
+func (m *Model) reviewImport() tea.Cmd {
+	imp := m.importing
+	for ; imp.review < len(imp.entries); imp.review++ {
+		entry := &imp.entries[imp.review]
+		if entry.track == nil && len(entry.candidates) > 0 {
+			m.searchDialog.Title = "Match " + entry.String()
+			m.searchDialog.Action = "choose"
+			m.isReviewImportActive = true
+			m.Send(search.UPDATE_SUGGESTIONS)
+			return nil
+		}
+	}
+
+	return m.finishImport()
+}
+
+func (m *Model) reviewImportControl(msg search.Control) tea.Cmd {
+	imp := m.importing
+	entry := &imp.entries[imp.review]
+
+	switch msg {
+	case search.SELECT:
+		m.isReviewImportActive = false
+
+		inputVal, ok := m.searchDialog.SuggestionValue()
+		if ok && inputVal != _SKIP_MATCH {
+			for i := range entry.candidates {
+				if candidateName(&entry.candidates[i]) == inputVal {
+					entry.track = &entry.candidates[i]
+					break
+				}
+			}
+		}
+
+		imp.review++
+		return m.reviewImport()
+	case search.CANCEL:
+		m.isReviewImportActive = false
+		m.importing = nil
+		log.Print(log.LVL_INFO, "playlist [%s] import is canceled", imp.name)
+	case search.UPDATE_SUGGESTIONS:
+		inputVal := strings.ToLower(m.searchDialog.InputValue())
+		suggestions := make([]string, 0, len(entry.candidates)+1)
+		for i := range entry.candidates {
+			name := candidateName(&entry.candidates[i])
+			if len(inputVal) > 0 && !strings.Contains(strings.ToLower(name), inputVal) {
+				continue
+			}
+			suggestions = append(suggestions, name)
+		}
+		suggestions = append(suggestions, _SKIP_MATCH)
+		m.searchDialog.SetSuggestions(suggestions)
+	}
+
+	return nil
+}
+
+// finishImport creates the user playlist with the found tracks in the playlist file order.
+func (m *Model) finishImport() tea.Cmd {
+	imp := m.importing
+	m.importing = nil
+
+	tracks := make([]api.Track, 0, len(imp.entries))
+	for i := range imp.entries {
+		if imp.entries[i].track != nil {
+			tracks = append(tracks, *imp.entries[i].track)
+		}
+	}
+	if len(tracks) == 0 {
+		m.tracker.ShowError("no tracks found to import")
+		return nil
+	}
+
+	pl, err := m.client.CreatePlaylist(imp.name, true)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to create playlist [%s]: %s", imp.name, err)
+		m.tracker.ShowError("playlist create")
+		return nil
+	}
+
+	diffTracks := make([]api.PlaylistDiffTrack, len(tracks))
+	for i := range tracks {
+		diffTracks[i] = api.NewPlaylistDiffTrack(&tracks[i])
+	}
+
+	diff := api.PlaylistDiff{}.Insert(0, diffTracks...)
+	newpl, err := m.client.ChangePlaylist(pl.Kind, pl.Revision, diff)
+	if errors.Is(err, api.ErrWrongRevision) {
+		// the new playlist is empty, so the change is valid for the actual revision too
+		var serverpl api.Playlist
+		serverpl, err = m.client.Playlist(pl.Kind)
+		if err == nil {
+			newpl, err = m.client.ChangePlaylist(pl.Kind, serverpl.Revision, diff)
+		}
+	}
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to add %d tracks to playlist [%s]: %s", len(tracks), imp.name, err)
+		m.tracker.ShowError("playlist add")
+		// the empty playlist is not left behind the failed import
+		err = m.client.RemovePlaylist(pl.Kind)
+		if err != nil {
+			log.Print(log.LVL_ERROR, "failed to remove playlist [%s]: %s", imp.name, err)
+		}
+		return nil
+	}
+	pl.Revision = newpl.Revision
+
+	index := m.sectionEnd(_PLAYLISTS_HEADER)
+	cmd := m.playlists.InsertItem(index, &playlist.Item{
+		Name:     pl.Title,
+		Kind:     pl.Kind,
+		Revision: pl.Revision,
+		Seed:     playlistSeed(m.client.UserId(), pl.Kind),
+		Active:   true,
+		Subitem:  true,
+		Tracks:   tracks,
+	})
+	if m.currentPlaylistIndex >= index {
+		m.currentPlaylistIndex++
+	}
+	if m.playlists.Index() >= index {
+		m.playlists.Select(m.playlists.Index() + 1)
+	}
+
+	log.Print(log.LVL_INFO, "imported %d of %d tracks to playlist [%s]", len(tracks), len(imp.entries), imp.name)
+	if missing := len(imp.entries) - len(tracks); missing > 0 {
+		m.tracker.ShowError(fmt.Sprintf("%d tracks not found", missing))
+	}
+	return cmd
+}
+
+// candidateName describes the found track to tell it apart from the other candidates.
+func candidateName(track *api.Track) string {
+	title := track.Title
+	if len(track.Version) > 0 {
+		title += " (" + track.Version + ")"
+	}
+
+	var album string
+	if len(track.Albums) > 0 {
+		album = track.Albums[0].Title + ", "
+	}
+
+	duration := track.DurationMs / 1000
+	return fmt.Sprintf("%s - %s [%s%d:%02d]", helpers.ArtistList(track.Artists), title, album, duration/60, duration%60)
+}
+
+// expandHome replaces the leading tilde of the path with the user home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
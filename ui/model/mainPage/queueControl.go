@@ -0,0 +1,97 @@
+package mainpage
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+func (m *Model) queueSelectedTracks(next bool) tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if selectedPlaylist.Kind == playlist.QUEUE || len(selectedPlaylist.Tracks) == 0 {
+		return nil
+	}
+
+	tracks := slices.DeleteFunc(m.selectedTracks(), func(t api.Track) bool { return !t.Available })
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	if next {
+		m.queue.PlayNext(tracks...)
+	} else {
+		m.queue.Add(tracks...)
+	}
+
+	return m.updateQueuePlaylist()
+}
+
+func (m *Model) queueSelectedPlaylist() tea.Cmd {
+	selectedPlaylist := m.playlists.SelectedItem()
+	if selectedPlaylist.Kind == playlist.QUEUE || len(selectedPlaylist.Tracks) == 0 {
+		return nil
+	}
+
+	tracks := make([]api.Track, 0, len(selectedPlaylist.Tracks))
+	for _, track := range selectedPlaylist.Tracks {
+		if track.Available {
+			tracks = append(tracks, track)
+		}
+	}
+
+	m.queue.Add(tracks...)
+	return m.updateQueuePlaylist()
+}
+
+func (m *Model) removeFromQueue(index int) tea.Cmd {
+	if !m.queue.Remove(index) {
+		return nil
+	}
+	return m.updateQueuePlaylist()
+}
+
+func (m *Model) moveInQueue(index, offset int) tea.Cmd {
+	if !m.queue.Move(index, index+offset) {
+		return nil
+	}
+
+	queuePlaylist, _ := m.playlists.GetFirst(playlist.QUEUE)
+	queuePlaylist.SelectedTrack = index + offset
+	return m.updateQueuePlaylist()
+}
+
+// playQueued removes the queued tracks up to the index and plays the last of them.
+// The current playlist position is preserved, so the playback continues from it when the queue runs out.
+func (m *Model) playQueued(index int) {
+	track, ok := m.queue.Take(index)
+	if !ok {
+		return
+	}
+
+	m.indicateCurrentTrackPlaying(false)
+	m.playingQueued = true
+	m.historyPos = -1
+	m.updateQueuePlaylist()
+	m.playTrack(&track)
+}
+
+func (m *Model) updateQueuePlaylist() tea.Cmd {
+	queuePlaylist, index := m.playlists.GetFirst(playlist.QUEUE)
+	if queuePlaylist == nil {
+		return nil
+	}
+
+	queuePlaylist.Tracks = slices.Clone(m.queue.Tracks())
+	if queuePlaylist.SelectedTrack >= len(queuePlaylist.Tracks) {
+		queuePlaylist.SelectedTrack = max(len(queuePlaylist.Tracks)-1, 0)
+	}
+
+	cmd := m.playlists.SetItem(index, queuePlaylist)
+	if m.playlists.SelectedItem().Kind == playlist.QUEUE {
+		m.displayPlaylist(queuePlaylist)
+	}
+
+	return cmd
+}
@@ -0,0 +1,210 @@
+package mainpage
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/config"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+)
+
+const (
+	_TRACK_FINISHED_THRESHOLD = 0.8
+)
+
+// rotorTracksMsg is the result of the rotor tracks request made in the background.
+type rotorTracksMsg struct {
+	pl        *playlist.Item
+	sessionId string
+	feedbacks []*api.RotorFeedback
+	tracks    api.StationTracks
+	err       error
+}
+
+// refillRotor requests more suggestions in the background
+// when the rotor playlist has less upcoming tracks than the configured lookahead.
+func (m *Model) refillRotor(pl *playlist.Item) {
+	if !pl.Rotor || pl.Refilling || m.client == nil || pl.Upcoming() >= config.Current.RotorLookahead {
+		return
+	}
+
+	pl.Refilling = true
+	client := m.client
+	sessionId := pl.SessionId
+	feedbacks := pl.Feedbacks
+	queue := slices.Clone(pl.Tracks)
+	pl.Feedbacks = nil
+
+	go func() {
+		tracks, err := client.RotorSessionTracks(sessionId, feedbacks, queue)
+		m.program.Send(rotorTracksMsg{pl: pl, sessionId: sessionId, feedbacks: feedbacks, tracks: tracks, err: err})
+	}()
+}
+
+// appendRotorTracks adds the suggestions received in the background to the rotor playlist.
+func (m *Model) appendRotorTracks(msg rotorTracksMsg) tea.Cmd {
+	pl := msg.pl
+	if pl.SessionId != msg.sessionId {
+		// the session was restarted while waiting for the tracks
+		return nil
+	}
+	pl.Refilling = false
+
+	if msg.err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain more rotor tracks: %s", msg.err)
+		m.tracker.ShowError("rotor tracks")
+		// the feedback will be sent with the next request
+		pl.Feedbacks = append(msg.feedbacks, pl.Feedbacks...)
+		return nil
+	}
+
+	if len(msg.feedbacks) > 0 {
+		log.Print(log.LVL_INFO, "%d feedback events sended with the tracks request", len(msg.feedbacks))
+	}
+	pl.SessionBatch = msg.tracks.BatchId
+	added := m.addSuggestions(pl, msg.tracks)
+
+	index := m.playlists.IndexOf(pl)
+	if index < 0 {
+		return nil
+	}
+
+	if added > 0 && m.resumeRotor && index == m.currentPlaylistIndex {
+		// the playback was waiting for the suggestions
+		if next := m.nextPlayable(pl); next >= 0 {
+			m.indicateCurrentTrackPlaying(false)
+			m.playTrack(&pl.Tracks[next])
+		}
+	}
+
+	cmd := m.playlists.SetItem(index, pl)
+	if added > 0 {
+		m.refillRotor(pl)
+	}
+	return cmd
+}
+
+// rotateTracks requests the suggestions and waits for them.
+// It's used when the playback reaches the end of the lookahead.
+func (m *Model) rotateTracks(pl *playlist.Item) bool {
+	if !pl.Rotor || m.client == nil {
+		return false
+	}
+
+	suggestedTracks, err := m.client.RotorSessionTracks(pl.SessionId, pl.Feedbacks, pl.Tracks)
+	if err != nil {
+		log.Print(log.LVL_ERROR, "failed to obtain more rotor tracks: %s", err)
+		m.tracker.ShowError("next track obtain failure")
+		return false
+	}
+
+	if len(pl.Feedbacks) > 0 {
+		log.Print(log.LVL_INFO, "%d feedback events sended with the tracks request", len(pl.Feedbacks))
+		pl.Feedbacks = nil
+	}
+	pl.SessionBatch = suggestedTracks.BatchId
+	return m.addSuggestions(pl, suggestedTracks) > 0
+}
+
+// addSuggestions appends the suggested tracks that are not upcoming yet and returns their number.
+func (m *Model) addSuggestions(pl *playlist.Item, suggestedTracks api.StationTracks) int {
+	upcoming := min(pl.CurrentTrack+1, len(pl.Tracks))
+
+	var added int
+	for _, suggestion := range suggestedTracks.Sequence {
+		isUpcoming := slices.ContainsFunc(pl.Tracks[upcoming:], func(t api.Track) bool {
+			return t.Id == suggestion.Track.Id
+		})
+		if isUpcoming {
+			continue
+		}
+
+		pl.AddTrackToEnd(&suggestion.Track)
+		added++
+	}
+
+	if added > 0 && m.playlists.SelectedItem().IsSame(pl) {
+		m.displayPlaylist(pl)
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+
+	return added
+}
+
+// skipSuggestion removes the upcoming suggestion from the rotor playlist, so it won't be played.
+func (m *Model) skipSuggestion(pl *playlist.Item, index int) tea.Cmd {
+	if index <= pl.CurrentTrack {
+		return nil
+	}
+
+	pl.Tracks = slices.Delete(pl.Tracks, index, index+1)
+	if pl.SelectedTrack >= len(pl.Tracks) {
+		pl.SelectedTrack = len(pl.Tracks) - 1
+	}
+
+	cmd := m.playlists.SetItem(m.playlists.IndexOf(pl), pl)
+	if m.playlists.SelectedItem().IsSame(pl) {
+		m.displayPlaylist(pl)
+		m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+	}
+
+	m.refillRotor(pl)
+	return cmd
+}
+
+// trackFeedbackEvent returns the finished or skipped event for the playing track.
+func (m *Model) trackFeedbackEvent() *api.RotorFeedbackEvent {
+	currTrack := m.tracker.CurrentTrack()
+	if currTrack == nil || len(currTrack.Id) == 0 {
+		return nil
+	}
+	var evType api.TrackEventType
+	if m.tracker.Progress() > _TRACK_FINISHED_THRESHOLD {
+		evType = api.EV_TRACK_FINISHED
+	} else {
+		evType = api.EV_TRACK_SKIPED
+	}
+	return api.NewTrackFeedbackEvent(evType, currTrack, m.tracker.Playtime().Seconds())
+}
+
+// rotorFeedback queues the feedback event to send it along with the next rotor tracks request,
+// so the suggested tracks take it into account.
+func (m *Model) rotorFeedback(pl *playlist.Item, ev *api.RotorFeedbackEvent) {
+	if ev == nil || !pl.Rotor {
+		return
+	}
+	pl.Feedbacks = append(pl.Feedbacks, api.NewFeedback(pl.SessionBatch, ev))
+	log.Print(log.LVL_INFO, "feedback event queued: "+ev.Type)
+}
+
+// flushRotorFeedback sends the queued feedback events without requesting new tracks.
+func (m *Model) flushRotorFeedback(pl *playlist.Item) {
+	feedbacks := pl.Feedbacks
+	pl.Feedbacks = nil
+	if len(feedbacks) == 0 || m.client == nil {
+		return
+	}
+
+	client := m.client
+	sessionId := pl.SessionId
+	m.reports.Add(1)
+	go func() {
+		defer m.reports.Done()
+		for _, fb := range feedbacks {
+			err := client.RotorSessionFeedback(sessionId, fb)
+			if err != nil {
+				log.Print(log.LVL_WARNIGN, "failed to send rotor feedback event %s: %s", fb.Event.Type, err)
+				continue
+			}
+			log.Print(log.LVL_INFO, "feedback event sended: "+fb.Event.Type)
+		}
+	}()
+}
+
+// finishRotor sends the radio finished event with all queued events when the rotor playback is over.
+func (m *Model) finishRotor(pl *playlist.Item) {
+	m.rotorFeedback(pl, api.NewRadioFeedbackEvent(api.EV_RADIO_FINISHED))
+	m.flushRotorFeedback(pl)
+}
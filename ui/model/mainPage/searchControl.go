@@ -49,20 +49,7 @@ func (m *Model) searchControl(msg search.Control) tea.Cmd {
 }
 
 func (m *Model) displaySearchResults(res api.SearchResult) tea.Cmd {
-	playlists := m.playlists.Items()
-	searchResIndex := len(playlists) + 2
-	for i, pl := range playlists {
-		if !pl.Active && !pl.Subitem && pl.Name == "search results:" {
-			playlists = playlists[:i-1]
-			searchResIndex = i + 1
-			break
-		}
-	}
-
-	playlists = append(playlists,
-		&playlist.Item{Name: "", Kind: playlist.NONE, Active: false, Subitem: false},
-		&playlist.Item{Name: "search results:", Kind: playlist.NONE, Active: false, Subitem: false},
-	)
+	var playlists []*playlist.Item
 
 	if len(res.Tracks.Results) > 0 {
 		playlists = append(playlists, &playlist.Item{
@@ -98,6 +85,7 @@ func (m *Model) displaySearchResults(res api.SearchResult) tea.Cmd {
 
 			playlists = append(playlists, &playlist.Item{
 				Name:    artist.Name,
+				Seed:    artistSeed(artist.Id),
 				Active:  true,
 				Subitem: true,
 				Tracks:  tracks,
@@ -125,6 +113,7 @@ func (m *Model) displaySearchResults(res api.SearchResult) tea.Cmd {
 				for i := range albumWithTracks.Volumes {
 					playlists = append(playlists, &playlist.Item{
 						Name:    fmt.Sprintf("%s vol.%d (%s)", albumWithTracks.Title, i, albumArtists),
+						Seed:    albumSeed(album.Id),
 						Active:  true,
 						Subitem: true,
 						Tracks:  albumWithTracks.Volumes[i],
@@ -133,6 +122,7 @@ func (m *Model) displaySearchResults(res api.SearchResult) tea.Cmd {
 			} else {
 				playlists = append(playlists, &playlist.Item{
 					Name:    fmt.Sprintf("%s (%s)", albumWithTracks.Title, albumArtists),
+					Seed:    albumSeed(album.Id),
 					Active:  true,
 					Subitem: true,
 					Tracks:  albumWithTracks.Volumes[0],
@@ -156,17 +146,26 @@ func (m *Model) displaySearchResults(res api.SearchResult) tea.Cmd {
 				continue
 			}
 
-			playlists = append(playlists, &playlist.Item{
-				Name:    pl.Title + " by " + pl.Owner.Name,
-				Active:  true,
-				Subitem: true,
-				Tracks:  playlistTracks,
-			})
+			playlists = append(playlists, m.remotePlaylistItem(&pl, playlistTracks))
 		}
 	}
 
+	return m.displayResults("search results:", playlists)
+}
+
+// displayResults replaces the results section at the sidebar end with the items and selects the first of them.
+func (m *Model) displayResults(header string, items []*playlist.Item) tea.Cmd {
+	playlists := m.playlists.Items()[:m.sectionEnd(_FOLLOWED_HEADER)]
+	resultsIndex := len(playlists) + 2
+
+	playlists = append(playlists,
+		&playlist.Item{Name: "", Kind: playlist.NONE, Active: false, Subitem: false},
+		&playlist.Item{Name: header, Kind: playlist.NONE, Active: false, Subitem: false},
+	)
+	playlists = append(playlists, items...)
+
 	cmd := m.playlists.SetItems(playlists)
-	m.playlists.Select(searchResIndex)
+	m.playlists.Select(resultsIndex)
 	m.Send(playlist.CURSOR_DOWN)
 
 	return cmd
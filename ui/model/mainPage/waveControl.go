@@ -0,0 +1,166 @@
+package mainpage
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dece2183/yamusic-tui/api"
+	"github.com/dece2183/yamusic-tui/library"
+	"github.com/dece2183/yamusic-tui/log"
+	"github.com/dece2183/yamusic-tui/ui/components/playlist"
+	"github.com/dece2183/yamusic-tui/ui/components/search"
+)
+
+type waveSeed struct {
+	name string
+	id   api.StationId
+}
+
+func playlistSeed(owner uint64, kind uint64) *api.StationId {
+	return &api.StationId{Type: "playlist", Tag: fmt.Sprintf("%d_%d", owner, kind)}
+}
+
+func albumSeed(album uint64) *api.StationId {
+	return &api.StationId{Type: "album", Tag: fmt.Sprint(album)}
+}
+
+func artistSeed(artist uint64) *api.StationId {
+	return &api.StationId{Type: "artist", Tag: fmt.Sprint(artist)}
+}
+
+// showWaveDialog offers to start the wave from the selected track, its artist, album or the selected playlist.
+func (m *Model) showWaveDialog() {
+	selectedPlaylist := m.playlists.SelectedItem()
+	m.waveSeeds = m.waveSeeds[:0]
+
+	if len(selectedPlaylist.Tracks) > 0 && !library.IsLocal(m.tracklist.SelectedItem().Track) {
+		track := m.tracklist.SelectedItem().Track
+		m.waveSeeds = append(m.waveSeeds, waveSeed{
+			name: "track " + track.Title,
+			id:   api.StationId{Type: "track", Tag: track.Id},
+		})
+		if len(track.Artists) > 0 {
+			m.waveSeeds = append(m.waveSeeds, waveSeed{
+				name: "artist " + track.Artists[0].Name,
+				id:   *artistSeed(track.Artists[0].Id),
+			})
+		}
+		if len(track.Albums) > 0 {
+			m.waveSeeds = append(m.waveSeeds, waveSeed{
+				name: "album " + track.Albums[0].Title,
+				id:   *albumSeed(track.Albums[0].Id),
+			})
+		}
+	}
+
+	if selectedPlaylist.Seed != nil {
+		m.waveSeeds = append(m.waveSeeds, waveSeed{
+			name: selectedPlaylist.Seed.Type + " " + selectedPlaylist.Name,
+			id:   *selectedPlaylist.Seed,
+		})
+	}
+
+	if len(m.waveSeeds) == 0 {
+		return
+	}
+
+	m.searchDialog.Title = "Start wave from"
+	m.searchDialog.Action = "start"
+	m.isWaveActive = true
+	m.Send(search.UPDATE_SUGGESTIONS)
+}
+
+func (m *Model) waveControl(msg search.Control) tea.Cmd {
+	switch msg {
+	case search.SELECT:
+		m.isWaveActive = false
+
+		inputVal, ok := m.searchDialog.SuggestionValue()
+		if !ok {
+			return nil
+		}
+
+		for _, seed := range m.waveSeeds {
+			if seed.name == inputVal {
+				return m.startWave(seed)
+			}
+		}
+	case search.CANCEL:
+		m.isWaveActive = false
+	case search.UPDATE_SUGGESTIONS:
+		inputVal := strings.ToLower(m.searchDialog.InputValue())
+		suggestions := make([]string, 0, len(m.waveSeeds))
+		for _, seed := range m.waveSeeds {
+			if len(inputVal) > 0 && !strings.Contains(strings.ToLower(seed.name), inputVal) {
+				continue
+			}
+			suggestions = append(suggestions, seed.name)
+		}
+		m.searchDialog.SetSuggestions(suggestions)
+	}
+
+	return nil
+}
+
+// startWave starts the rotor session seeded from the track, artist, album or playlist
+// and plays it from the new sidebar item placed under my wave.
+// The wave that is already started from the same seed is reused.
+func (m *Model) startWave(seed waveSeed) tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+
+	playlists := m.playlists.Items()
+	waveIndex := 1
+	for i, pl := range playlists {
+		if pl.Kind == playlist.WAVE && pl.StationId == seed.id {
+			m.playlists.Select(i)
+			pl.SelectedTrack = pl.CurrentTrack
+			m.displayPlaylist(pl)
+			if i != m.currentPlaylistIndex {
+				m.playSelectedPlaylist(pl.CurrentTrack)
+			} else {
+				m.indicateCurrentTrackPlaying(m.tracker.IsPlaying())
+			}
+			return nil
+		}
+		if pl.Kind == playlist.MYWAVE || pl.Kind == playlist.WAVE {
+			waveIndex = i + 1
+		}
+	}
+
+	session, err := m.client.RotorNewSession(seed.id)
+	if err != nil || len(session.Sequence) == 0 {
+		log.Print(log.LVL_ERROR, "unable to init rotor session from [%s]: %v", seed.id.String(), err)
+		m.tracker.ShowError("unable to init rotor session")
+		return nil
+	}
+
+	wave := &playlist.Item{
+		Name:         seed.name,
+		Kind:         playlist.WAVE,
+		StationId:    seed.id,
+		SessionId:    session.RadioSessionId,
+		SessionBatch: session.BatchId,
+		Active:       true,
+		Subitem:      true,
+		Rotor:        true,
+	}
+	for _, s := range session.Sequence {
+		wave.Tracks = append(wave.Tracks, s.Track)
+	}
+
+	cmd := m.playlists.InsertItem(waveIndex, wave)
+	if m.currentPlaylistIndex >= waveIndex {
+		m.currentPlaylistIndex++
+	}
+
+	m.playlists.Select(waveIndex)
+	m.displayPlaylist(wave)
+	m.tracklist.Shufflable = false
+	m.tracklist.Reorderable = false
+	m.playSelectedPlaylist(0)
+
+	return cmd
+}
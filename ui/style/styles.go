@@ -35,7 +35,12 @@ var (
 	IconStop       = "■"
 	IconLiked      = "💛"
 	IconNotLiked   = "🤍"
+	IconDisliked   = "👎"
+	IconMarked     = "•"
 	IconCached     = "💿"
+	IconRepeatOne  = "🔂"
+	IconRepeatAll  = "🔁"
+	IconShuffle    = "🔀"
 	IconDotLight   = "•"
 	IconDotDark    = "•"
 	IconVolumeOff  = "🔇"
@@ -110,7 +115,12 @@ func Apply(style *config.Style) {
 	IconStop = style.Icons.Stop
 	IconLiked = style.Icons.Liked
 	IconNotLiked = style.Icons.NotLiked
+	IconDisliked = style.Icons.Disliked
+	IconMarked = AccentTextStyle.Render(style.Icons.Marked)
 	IconCached = style.Icons.Cached
+	IconRepeatOne = style.Icons.RepeatOne
+	IconRepeatAll = style.Icons.RepeatAll
+	IconShuffle = style.Icons.Shuffle
 	IconDotLight = lipgloss.NewStyle().Foreground(LyricsCurrentTextColor).Render(style.Icons.LyricsDot)
 	IconDotDark = lipgloss.NewStyle().Foreground(LyricsPreviosTextColor).Render(style.Icons.LyricsDot)
 	IconVolumeOff = style.Icons.VolumeOff